@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"gopkg.in/yaml.v3"
+)
+
+// shownSetting is one effective setting in the config show output
+type shownSetting struct {
+	Key    string
+	Value  string
+	Source string
+}
+
+// shownSettings keeps settings in their documented order in both YAML and JSON
+type shownSettings struct {
+	items       []shownSetting
+	withSources bool
+}
+
+// shownGame is one game's effective configuration
+type shownGame struct {
+	ID       string              `yaml:"id,omitempty" json:"id,omitempty"`
+	Settings shownSettings       `yaml:"settings" json:"settings"`
+	Players  []userparser.Player `yaml:"players,omitempty" json:"players,omitempty"`
+}
+
+// entry returns the output value of a setting: the value alone, or the value with its source
+func (s shownSettings) entry(item shownSetting) any {
+	if !s.withSources {
+		return item.Value
+	}
+	return struct {
+		Value  string `yaml:"value" json:"value"`
+		Source string `yaml:"source" json:"source"`
+	}{item.Value, item.Source}
+}
+
+func (s shownSettings) MarshalYAML() (any, error) {
+	node := &yaml.Node{Kind: yaml.MappingNode}
+	for _, item := range s.items {
+		var val yaml.Node
+		if err := val.Encode(s.entry(item)); err != nil {
+			return nil, err
+		}
+		node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: item.Key}, &val)
+	}
+	return node, nil
+}
+
+func (s shownSettings) MarshalJSON() ([]byte, error) {
+	var b strings.Builder
+	b.WriteString("{")
+	for i, item := range s.items {
+		key, _ := json.Marshal(item.Key)
+		val, err := json.Marshal(s.entry(item))
+		if err != nil {
+			return nil, err
+		}
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.Write(key)
+		b.WriteString(":")
+		b.Write(val)
+	}
+	b.WriteString("}")
+	return []byte(b.String()), nil
+}
+
+// runConfig dispatches the config subcommands.
+func runConfig(args []string) int {
+	if len(args) == 0 || args[0] != "show" {
+		fmt.Fprintf(os.Stderr, "Usage: %s config show [flags]\n", os.Args[0])
+		return 2
+	}
+	return runConfigShow(args[1:])
+}
+
+// runConfigShow prints the fully resolved configuration of every game with secrets redacted.
+func runConfigShow(args []string) int {
+	fs := flag.NewFlagSet("config show", flag.ExitOnError)
+	format := fs.String("format", "yaml", "output format: yaml or json")
+	withSources := fs.Bool("sources", true, "show where each value came from; disable to print values only")
+	options := configFlags(fs, os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s config show [flags]\n\nPrints the effective configuration after flags, environment, config file and defaults.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	restore := redact.CaptureStdout()
+	defer restore()
+
+	games, err := types.Load(options())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+
+	shown := make([]shownGame, 0, len(games))
+	for _, cfg := range games {
+		shown = append(shown, showGame(cfg, *withSources))
+	}
+	doc := map[string]any{"games": shown}
+
+	switch *format {
+	case "yaml":
+		enc := yaml.NewEncoder(os.Stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		enc.Close()
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(doc); err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "⚠️ Unknown format '%s' (expected yaml or json)\n", *format)
+		return 1
+	}
+	return 0
+}
+
+// showGame collects a game's effective settings in documented order, redacting secrets
+func showGame(cfg types.Config, withSources bool) shownGame {
+	g := shownGame{ID: cfg.ID, Players: cfg.Players, Settings: shownSettings{withSources: withSources}}
+	for _, s := range types.Settings {
+		item := shownSetting{
+			Key:    strings.ToLower(s.Key),
+			Value:  cfg.Setting(s.Key),
+			Source: cfg.Sources[s.Key].String(),
+		}
+		if s.Secret && item.Value != "" {
+			item.Value = redact.Placeholder
+		}
+		// Show the extensions the profile supplies when none are configured
+		if s.Key == "ALLOWED_EXTENSIONS" && item.Value == "" && len(cfg.AllowedExtensions) > 0 {
+			item.Value = strings.Join(cfg.AllowedExtensions, ",")
+			item.Source = "default (profile " + cfg.Profile().ID + ")"
+		}
+		g.Settings.items = append(g.Settings.items, item)
+	}
+	return g
+}
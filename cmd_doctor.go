@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/discordapi"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncthing"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// doctorReport counts and prints the results of doctor checks
+type doctorReport struct {
+	failed int
+	warned int
+}
+
+func (r *doctorReport) pass(format string, args ...any) {
+	fmt.Printf("  ✅ "+format+"\n", args...)
+}
+
+func (r *doctorReport) warn(format string, args ...any) {
+	r.warned++
+	fmt.Printf("  ⚠️ "+format+"\n", args...)
+}
+
+func (r *doctorReport) fail(format string, args ...any) {
+	r.failed++
+	fmt.Printf("  ❌ "+format+"\n", args...)
+}
+
+// runDoctor checks every game's setup end to end and prints a pass/fail report.
+func runDoctor(args []string) int {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	online := fs.Bool("online", false, "contact Discord to check the webhook and, with DISCORD_BOT_TOKEN, every player; also check Syncthing when configured")
+	options := configFlags(fs, os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s doctor [flags]\n\nChecks the configuration, watch directory, roster, existing saves and webhook.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	restore := redact.CaptureStdout()
+	defer restore()
+
+	games, err := types.Load(options())
+	if err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	r := &doctorReport{}
+	for _, cfg := range games {
+		fmt.Printf("🩺 %sChecking game %s\n", cfg.Label(), cfg.GameName)
+		fatal := r.checkConfig(cfg)
+		// Problems validation already failed on are not reported twice
+		if !fatal["WATCH_DIRECTORY"] {
+			r.checkDirectory(cfg)
+		}
+		var users []userparser.UserMapping
+		if !fatal["USER_MAPPINGS"] && !fatal["ROSTER_FILE"] && !fatal["players"] {
+			users = r.checkPlayers(cfg)
+		}
+		r.checkFiles(cfg, users)
+		r.checkWebhooks(cfg, *online)
+		if *online && cfg.DiscordBotToken != "" && len(users) > 0 {
+			r.checkDiscordUsers(cfg, users)
+		}
+		if *online && cfg.SyncthingAPIKey != "" {
+			r.checkSyncthing(cfg)
+		}
+	}
+
+	switch {
+	case r.failed > 0:
+		fmt.Printf("🩺 %d check(s) failed, %d warning(s)\n", r.failed, r.warned)
+		return 1
+	case r.warned > 0:
+		fmt.Printf("🩺 All checks passed with %d warning(s)\n", r.warned)
+	default:
+		fmt.Println("🩺 All checks passed")
+	}
+	return 0
+}
+
+// checkConfig reports the problems found by config validation and returns the keys that failed
+func (r *doctorReport) checkConfig(cfg types.Config) map[string]bool {
+	var verr *types.ValidationError
+	if err := cfg.Validate(); !errors.As(err, &verr) {
+		r.pass("Configuration is valid")
+		return nil
+	}
+	fatal := make(map[string]bool)
+	for _, p := range verr.Problems {
+		if p.Fatal {
+			fatal[p.Key] = true
+			r.fail("%s", p)
+		} else {
+			r.warn("%s", p)
+		}
+	}
+	return fatal
+}
+
+// checkDirectory verifies the watch directory can be read and written
+func (r *doctorReport) checkDirectory(cfg types.Config) {
+	if store.IsRemote(cfg.WatchDirectory) {
+		r.checkRemoteStore(cfg)
+		return
+	}
+
+	entries, err := os.ReadDir(cfg.WatchDirectory)
+	if err != nil {
+		r.fail("Watch directory %s cannot be read: %v", cfg.WatchDirectory, err)
+		return
+	}
+	r.pass("Watch directory %s is readable (%d entries)", cfg.WatchDirectory, len(entries))
+
+	probe, err := os.CreateTemp(cfg.WatchDirectory, ".doctor-*")
+	if err != nil {
+		if cfg.AutoRename != types.AutoRenameOff {
+			r.fail("Watch directory is not writable, which AUTO_RENAME=%s needs: %v", cfg.AutoRename, err)
+		} else {
+			r.warn("Watch directory is not writable; only needed for AUTO_RENAME: %v", err)
+		}
+		return
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+	r.pass("Watch directory is writable")
+}
+
+// checkRemoteStore lists a remote watch directory; writing is left untested to keep the store clean
+func (r *doctorReport) checkRemoteStore(cfg types.Config) {
+	st, err := store.Open(cfg.WatchDirectory, cfg.StoreOptions())
+	if err != nil {
+		r.fail("Watch directory %s cannot be opened: %v", cfg.WatchDirectory, err)
+		return
+	}
+	names, err := st.List()
+	if err != nil {
+		r.fail("Watch directory %s cannot be listed: %v", cfg.WatchDirectory, err)
+		return
+	}
+	r.pass("Watch directory %s is readable (%d entries)", cfg.WatchDirectory, len(names))
+}
+
+// checkPlayers loads the players the monitor would use
+func (r *doctorReport) checkPlayers(cfg types.Config) []userparser.UserMapping {
+	rosterPath := cfg.RosterPath()
+	users, skipped, err := cfg.LoadPlayers()
+	if skipped != nil {
+		// checkConfig has already warned about the ignored roster
+		rosterPath = ""
+	}
+	if err != nil {
+		r.fail("Players cannot be loaded: %v", err)
+		return nil
+	}
+	source := "USER_MAPPINGS"
+	switch {
+	case rosterPath != "":
+		source = "roster file " + rosterPath
+	case len(cfg.Players) > 0:
+		source = "the config file"
+	}
+	names := make([]string, 0, len(users))
+	for _, u := range users {
+		names = append(names, u.Username)
+	}
+	r.pass("%d players loaded from %s: %s", len(users), source, strings.Join(names, ", "))
+	return users
+}
+
+// checkFiles dry-runs the monitor's file handling on every file in the watch directory
+func (r *doctorReport) checkFiles(cfg types.Config, users []userparser.UserMapping) {
+	st, err := store.Open(cfg.WatchDirectory, cfg.StoreOptions())
+	if err != nil {
+		return
+	}
+	names, err := st.List()
+	if err != nil {
+		return
+	}
+	saves := 0
+	for _, name := range names {
+		v := monitor.Classify(name, cfg, users)
+		switch v.Kind {
+		case monitor.VerdictSave:
+			saves++
+			fmt.Printf("    📄 %s: save, turn %d, %s's turn\n", name, v.Turn, v.Player)
+		case monitor.VerdictMisnamed:
+			if v.Corrected != "" {
+				r.warn("%s: misnamed, %s; expected %s", name, v.Reason, v.Corrected)
+			} else {
+				r.warn("%s: misnamed, %s", name, v.Reason)
+			}
+		case monitor.VerdictUnknown:
+			r.warn("%s: save for turn %d, but %s", name, v.Turn, v.Reason)
+		case monitor.VerdictConflict:
+			r.warn("%s: %s; keep the right version and delete the others", name, v.Reason)
+		default:
+			fmt.Printf("    ➖ %s: %s, %s\n", name, v.Kind, v.Reason)
+		}
+	}
+	if saves == 0 {
+		fmt.Println("    ℹ️ No saves of this game yet")
+	}
+}
+
+// checkWebhooks validates the webhook URLs and optionally asks Discord about them
+func (r *doctorReport) checkWebhooks(cfg types.Config, online bool) {
+	hooks := []struct{ key, url string }{
+		{"DISCORD_WEBHOOK_URL", cfg.WebhookURL},
+		{"ADMIN_WEBHOOK_URL", cfg.AdminWebhookURL},
+	}
+	for _, h := range hooks {
+		if h.url == "" {
+			continue
+		}
+		if err := webhook.CheckURL(h.url); err != nil {
+			r.warn("%s does not look like a Discord webhook: %v", h.key, err)
+		} else {
+			r.pass("%s looks like a Discord webhook", h.key)
+		}
+		if !online {
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		info, err := webhook.FetchInfo(ctx, h.url)
+		cancel()
+		if err != nil {
+			r.fail("%s could not be fetched: %v", h.key, err)
+		} else {
+			r.pass("%s is the webhook '%s' in channel %s", h.key, info.Name, info.ChannelID)
+		}
+	}
+}
+
+// checkDiscordUsers looks up every player with the bot token
+func (r *doctorReport) checkDiscordUsers(cfg types.Config, users []userparser.UserMapping) {
+	client := discordapi.NewClient(cfg.DiscordAPIURL, cfg.DiscordBotToken)
+	for _, u := range users {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		name, err := client.LookupDisplayName(ctx, cfg.DiscordGuildID, u.DiscordID)
+		cancel()
+		if err != nil {
+			r.fail("Discord user for %s cannot be found: %v", u.Username, err)
+			continue
+		}
+		r.pass("%s is Discord user %s", u.Username, name)
+	}
+}
+
+// checkSyncthing finds the watch directory's Syncthing folder and reports disconnected peers
+func (r *doctorReport) checkSyncthing(cfg types.Config) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	client := syncthing.NewClient(cfg.SyncthingURL, cfg.SyncthingAPIKey)
+	folder, err := client.FindFolder(ctx, cfg.SyncthingFolder, cfg.WatchDirectory)
+	if err != nil {
+		r.fail("Syncthing: %v", err)
+		return
+	}
+	peers, err := client.Peers(ctx, folder)
+	if err != nil {
+		r.fail("Syncthing: %v", err)
+		return
+	}
+	r.pass("Syncthing shares the watch directory as folder %s with %d peer(s)", folder.ID, len(peers))
+	for _, p := range peers {
+		if p.Connected {
+			r.pass("Syncthing peer %s is connected", p.Label())
+		} else {
+			r.warn("Syncthing peer %s is disconnected", p.Label())
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/health"
+)
+
+// heartbeatWriteInterval is how often heartbeats are saved for the healthcheck subcommand
+const heartbeatWriteInterval = 15 * time.Second
+
+// heartbeatFile returns the heartbeat file path from HEARTBEAT_FILE or the default
+func heartbeatFile() string {
+	if path := os.Getenv("HEARTBEAT_FILE"); path != "" {
+		return path
+	}
+	return health.DefaultFile
+}
+
+// writeHeartbeats saves the monitors' heartbeats to path until ctx is canceled
+func writeHeartbeats(ctx context.Context, path string) {
+	ticker := time.NewTicker(heartbeatWriteInterval)
+	defer ticker.Stop()
+
+	failing := false
+	for {
+		if err := health.WriteFile(path); err != nil && !failing {
+			log.Printf("⚠️ Cannot write heartbeat file %s, the healthcheck command will fail: %v\n", path, err)
+			failing = true
+		} else if err == nil {
+			failing = false
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runHealthcheck exits non-zero if a monitor's heartbeat is stale or its watch directory is
+// unreadable. It needs no shell or HTTP client, so it works as a Docker HEALTHCHECK.
+func runHealthcheck(args []string) int {
+	fs := flag.NewFlagSet("healthcheck", flag.ExitOnError)
+	path := fs.String("file", heartbeatFile(), "heartbeat file written by the running bot (or set HEARTBEAT_FILE)")
+	fs.Parse(args)
+
+	states, err := health.ReadFile(*path)
+	if err != nil {
+		fmt.Printf("❌ Cannot read heartbeat file: %v\n", err)
+		return 1
+	}
+	rep := health.Evaluate(states, time.Now())
+
+	healthy := len(rep.Games) > 0
+	for _, g := range rep.Games {
+		name := g.ID
+		if name == "" {
+			name = "monitor"
+		}
+		if !g.Live || !g.DirReadable {
+			healthy = false
+			fmt.Printf("❌ %s: %s\n", name, g.Problem)
+		} else if g.LastBeat == "" {
+			fmt.Printf("✅ %s: starting up\n", name)
+		} else {
+			fmt.Printf("✅ %s: last heartbeat %s\n", name, g.LastBeat)
+		}
+	}
+	if !healthy {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/discovery"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"gopkg.in/yaml.v3"
+)
+
+// discordIDPlaceholder marks Discord IDs that still have to be filled in by hand.
+const discordIDPlaceholder = "DISCORD_ID"
+
+// runInit scans the watch directory and prints a ready-to-edit .env or roster file.
+func runInit(args []string) int {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	gameID := fs.String("game", "", "ID of the game to set up when several are configured")
+	dir := fs.String("dir", "", "directory containing existing saves (default WATCH_DIRECTORY)")
+	profileID := fs.String("profile", "", "game profile used to parse save names (default GAME_PROFILE)")
+	format := fs.String("format", "env", "output format: env or yaml")
+	options := configFlags(fs, os.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s init [flags]\n\nInfers GAME_NAME and the player order from existing saves and prints a config to edit.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	games, err := types.Load(options())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	cfg, ok := selectGame(games, *gameID)
+	if !ok {
+		ids := make([]string, 0, len(games))
+		for _, g := range games {
+			ids = append(ids, g.ID)
+		}
+		if *gameID == "" {
+			fmt.Fprintf(os.Stderr, "⚠️ Several games are configured; choose one with --game (available: %s)\n", strings.Join(ids, ", "))
+		} else {
+			fmt.Fprintf(os.Stderr, "⚠️ Unknown game '%s' (available: %s)\n", *gameID, strings.Join(ids, ", "))
+		}
+		return 1
+	}
+	if *dir == "" {
+		*dir = cfg.WatchDirectory
+	}
+	if *profileID == "" {
+		*profileID = cfg.GameProfile
+	}
+
+	profile, ok := game.Lookup(*profileID)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "⚠️ Unknown game profile '%s' (available: %s)\n", *profileID, strings.Join(game.Names(), ", "))
+		return 1
+	}
+	exts := cfg.AllowedExtensions
+	if cfg.AllowedExtensionsRaw == "" {
+		exts = profile.Extensions
+	}
+
+	res, err := discovery.Scan(*dir, profile, exts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+		return 1
+	}
+	if res.GameName == "" {
+		fmt.Fprintf(os.Stderr, "❓ No saves matching the %s naming format found in %s\n", profile.Title, *dir)
+		return 1
+	}
+
+	// Report what was found on stderr so stdout can be redirected into a file
+	fmt.Fprintf(os.Stderr, "🔍 Found %d save(s) for game '%s' with %d player(s)\n", res.Games[res.GameName], res.GameName, len(res.Players))
+	for g, n := range res.Games {
+		if g != res.GameName {
+			fmt.Fprintf(os.Stderr, "ℹ️ Also found %d save(s) for game '%s'; run with a cleaned directory to set it up separately\n", n, g)
+		}
+	}
+	for _, name := range res.Unparsed {
+		fmt.Fprintf(os.Stderr, "❓ Could not parse save name: %s\n", name)
+	}
+	if len(res.Players) < 2 {
+		fmt.Fprintln(os.Stderr, "⚠️ Fewer than two players found; add the missing players by hand")
+	}
+	fmt.Fprintf(os.Stderr, "✏️ Replace each %s placeholder with the player's Discord user ID and check the order\n", discordIDPlaceholder)
+
+	switch *format {
+	case "env":
+		fmt.Print(renderEnv(res, *dir, profile))
+	case "yaml":
+		out, err := renderRoster(res)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "❌ %v\n", err)
+			return 1
+		}
+		fmt.Print(out)
+	default:
+		fmt.Fprintf(os.Stderr, "⚠️ Unknown format '%s' (expected env or yaml)\n", *format)
+		return 1
+	}
+	return 0
+}
+
+// selectGame picks the game with the given ID, or the only configured game when id is empty
+func selectGame(games []types.Config, id string) (types.Config, bool) {
+	if id == "" {
+		if len(games) == 1 {
+			return games[0], true
+		}
+		return types.Config{}, false
+	}
+	for _, g := range games {
+		if g.ID == id {
+			return g, true
+		}
+	}
+	return types.Config{}, false
+}
+
+// renderEnv renders the inferred game as .env lines
+func renderEnv(res *discovery.Result, dir string, profile game.Profile) string {
+	players := make([]userparser.Player, 0, len(res.Players))
+	for _, p := range res.Players {
+		players = append(players, userparser.Player{Order: p.Order, Name: p.Name, DiscordID: discordIDPlaceholder})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "GAME_NAME=%s\n", res.GameName)
+	if profile.ID != game.DefaultProfile {
+		fmt.Fprintf(&b, "GAME_PROFILE=%s\n", profile.ID)
+	}
+	// Profiles without their own extensions need the ones the saves use
+	if len(profile.Extensions) == 0 && len(res.Extensions) > 0 {
+		fmt.Fprintf(&b, "ALLOWED_EXTENSIONS=%s\n", strings.Join(res.Extensions, ","))
+	}
+	fmt.Fprintf(&b, "WATCH_DIRECTORY=%s\n", dir)
+	fmt.Fprintf(&b, "USER_MAPPINGS=%s\n", formatUserMappings(players))
+	fmt.Fprintf(&b, "DISCORD_WEBHOOK_URL=\n")
+	return b.String()
+}
+
+// formatUserMappings renders players in the USER_MAPPINGS format, quoting names with spaces or commas
+func formatUserMappings(players []userparser.Player) string {
+	mappings := make([]string, 0, len(players))
+	for _, p := range players {
+		fields := []string{fmt.Sprint(p.Order), quoteName(p.Name), p.DiscordID}
+		for _, alias := range p.Aliases {
+			fields = append(fields, quoteName(alias))
+		}
+		mappings = append(mappings, strings.Join(fields, " "))
+	}
+	return strings.Join(mappings, ",")
+}
+
+func quoteName(name string) string {
+	if strings.ContainsAny(name, " ,") {
+		return `"` + name + `"`
+	}
+	return name
+}
+
+// renderRoster renders the inferred players as a roster file
+func renderRoster(res *discovery.Result) (string, error) {
+	roster := userparser.Roster{}
+	for _, p := range res.Players {
+		roster.Players = append(roster.Players, userparser.Player{
+			Order:     p.Order,
+			Name:      p.Name,
+			DiscordID: discordIDPlaceholder,
+		})
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Roster inferred from saves of game %s\n", res.GameName)
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(roster); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
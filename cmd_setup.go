@@ -0,0 +1,339 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+	"gopkg.in/yaml.v3"
+)
+
+// setupFile is the config file written by the setup wizard
+type setupFile struct {
+	GameName          string              `yaml:"game_name"`
+	GameProfile       string              `yaml:"game_profile,omitempty"`
+	AllowedExtensions string              `yaml:"allowed_extensions,omitempty"`
+	WatchDirectory    string              `yaml:"watch_directory"`
+	DiscordWebhookURL string              `yaml:"discord_webhook_url"`
+	Players           []userparser.Player `yaml:"players"`
+}
+
+// wizard asks for setup answers on stdin, or takes them from flags in non-interactive mode
+type wizard struct {
+	in          *bufio.Reader
+	interactive bool
+}
+
+// errNoAnswer is returned when a required answer is missing in non-interactive mode
+var errNoAnswer = errors.New("no value given")
+
+// runSetup asks for the game's settings, checks each answer and writes a .env or config file.
+func runSetup(args []string) int {
+	fs := flag.NewFlagSet("setup", flag.ExitOnError)
+	nonInteractive := fs.Bool("non-interactive", false, "take every answer from flags instead of prompting")
+	gameName := fs.String("game-name", "", "name prefix for save files")
+	profileID := fs.String("profile", game.DefaultProfile, "game profile: "+strings.Join(game.Names(), ", "))
+	extensions := fs.String("extensions", "", "comma-separated save file extensions, required by profiles without their own")
+	dir := fs.String("dir", "./data", "directory to monitor for save files")
+	createDir := fs.Bool("create-dir", false, "create the watch directory if it does not exist")
+	webhookURL := fs.String("webhook-url", "", "Discord webhook URL for notifications")
+	players := fs.String("players", "", "players in USER_MAPPINGS format, e.g. '1 Alice 123...,2 Bob 456...'")
+	format := fs.String("format", "env", "output format: env or yaml")
+	output := fs.String("output", "", "file to write (default .env or bot.yaml)")
+	force := fs.Bool("force", false, "overwrite the output file if it exists")
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s setup [flags]\n\nAsks for the game's settings, checks them and writes a .env or config file.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	if *format != "env" && *format != "yaml" {
+		fmt.Printf("⚠️ Unknown format '%s' (expected env or yaml)\n", *format)
+		return 1
+	}
+	if *output == "" {
+		*output = ".env"
+		if *format == "yaml" {
+			*output = "bot.yaml"
+		}
+	}
+
+	w := &wizard{in: bufio.NewReader(os.Stdin), interactive: !*nonInteractive}
+	if w.interactive {
+		fmt.Println("🧙 Setting up a new game. Press Enter to accept the value in brackets.")
+	}
+
+	var err error
+	values := map[string]string{}
+	if values["GAME_PROFILE"], err = w.ask("Game profile", *profileID, func(v string) error {
+		if _, ok := game.Lookup(v); !ok {
+			return fmt.Errorf("unknown profile (available: %s)", strings.Join(game.Names(), ", "))
+		}
+		return nil
+	}); err != nil {
+		return setupFailed("GAME_PROFILE", err)
+	}
+	if profile, _ := game.Lookup(values["GAME_PROFILE"]); len(profile.Extensions) == 0 {
+		if values["ALLOWED_EXTENSIONS"], err = w.ask("Save file extensions, e.g. sav", *extensions, settingCheck("ALLOWED_EXTENSIONS", values)); err != nil {
+			return setupFailed("ALLOWED_EXTENSIONS", err)
+		}
+	}
+	if values["GAME_NAME"], err = w.ask("Game name used in save names", *gameName, settingCheck("GAME_NAME", values)); err != nil {
+		return setupFailed("GAME_NAME", err)
+	}
+	if values["WATCH_DIRECTORY"], err = w.ask("Directory with the save files", *dir, func(v string) error {
+		if _, statErr := os.Stat(v); os.IsNotExist(statErr) && (*createDir || w.confirm(fmt.Sprintf("Directory %s does not exist. Create it?", v), true)) {
+			if err := os.MkdirAll(v, 0o755); err != nil {
+				return err
+			}
+			fmt.Printf("📁 Created %s\n", v)
+		}
+		return settingCheck("WATCH_DIRECTORY", values)(v)
+	}); err != nil {
+		return setupFailed("WATCH_DIRECTORY", err)
+	}
+	if values["DISCORD_WEBHOOK_URL"], err = w.ask("Discord webhook URL", *webhookURL, func(v string) error {
+		if err := settingCheck("DISCORD_WEBHOOK_URL", values)(v); err != nil {
+			return err
+		}
+		if err := webhook.CheckURL(v); err != nil {
+			fmt.Printf("⚠️ This does not look like a Discord webhook: %v\n", err)
+		}
+		return nil
+	}); err != nil {
+		return setupFailed("DISCORD_WEBHOOK_URL", err)
+	}
+
+	roster, err := w.askPlayers(*players)
+	if err != nil {
+		return setupFailed("players", err)
+	}
+
+	// Check the answers together, the same way the bot will at startup
+	cfg := types.FromValues(values)
+	cfg.Players = roster
+	var verr *types.ValidationError
+	if errors.As(cfg.Validate(), &verr) {
+		for _, p := range verr.Problems {
+			if p.Fatal {
+				fmt.Printf("❌ %s\n", p)
+			} else {
+				fmt.Printf("⚠️ %s\n", p)
+			}
+		}
+		if verr.Fatal() {
+			fmt.Println("🛑 Not writing a configuration with the problems above")
+			return 1
+		}
+	}
+
+	var content string
+	if *format == "env" {
+		content = renderSetupEnv(values, roster)
+	} else if content, err = renderSetupYAML(values, roster); err != nil {
+		fmt.Printf("❌ %v\n", err)
+		return 1
+	}
+
+	if _, err := os.Stat(*output); err == nil && !*force && !w.confirm(fmt.Sprintf("%s already exists. Overwrite it?", *output), false) {
+		fmt.Printf("🛑 %s exists; use --force to overwrite it\n", *output)
+		return 1
+	}
+	// The file holds the webhook token, so keep it private
+	if err := os.WriteFile(*output, []byte(content), 0o600); err != nil {
+		fmt.Printf("❌ Failed to write %s: %v\n", *output, err)
+		return 1
+	}
+	fmt.Printf("✅ Wrote %s for game %s with %d players\n", *output, values["GAME_NAME"], len(roster))
+	if *format == "yaml" {
+		fmt.Printf("▶️ Start the bot with --config %s\n", *output)
+	}
+	return 0
+}
+
+// setupFailed reports an answer that could not be accepted
+func setupFailed(what string, err error) int {
+	fmt.Printf("❌ %s: %v\n", what, err)
+	return 1
+}
+
+// settingCheck returns a check that validates a single setting with the bot's own validation,
+// using the answers given so far for context
+func settingCheck(key string, values map[string]string) func(string) error {
+	return func(v string) error {
+		answers := make(map[string]string, len(values)+1)
+		for k, val := range values {
+			answers[k] = val
+		}
+		answers[key] = v
+		var verr *types.ValidationError
+		if !errors.As(types.FromValues(answers).Validate(), &verr) {
+			return nil
+		}
+		for _, p := range verr.Problems {
+			if p.Key != key {
+				continue
+			}
+			if p.Fatal {
+				return errors.New(p.Message)
+			}
+			fmt.Printf("⚠️ %s\n", p.Message)
+		}
+		return nil
+	}
+}
+
+// ask prompts for a value until check accepts it. In non-interactive mode the default is
+// the answer and a rejected answer is an error.
+func (w *wizard) ask(label, def string, check func(string) error) (string, error) {
+	if !w.interactive {
+		if strings.TrimSpace(def) == "" {
+			return "", errNoAnswer
+		}
+		return def, check(def)
+	}
+	for {
+		v, err := w.prompt(label, def)
+		if err != nil {
+			return "", err
+		}
+		if v == "" {
+			fmt.Println("⚠️ A value is required")
+			continue
+		}
+		if err := check(v); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+			continue
+		}
+		return v, nil
+	}
+}
+
+// confirm asks a yes/no question; in non-interactive mode it returns false
+func (w *wizard) confirm(question string, def bool) bool {
+	if !w.interactive {
+		return false
+	}
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	v, err := w.prompt(question+" ["+hint+"]", "")
+	if err != nil || v == "" {
+		return def
+	}
+	return strings.HasPrefix(strings.ToLower(v), "y")
+}
+
+// prompt prints label and reads one line, returning def for an empty answer
+func (w *wizard) prompt(label, def string) (string, error) {
+	if def != "" {
+		fmt.Printf("%s [%s]: ", label, def)
+	} else {
+		fmt.Printf("%s: ", label)
+	}
+	line, err := w.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("no answer: input closed")
+	}
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return def, nil
+	}
+	return line, nil
+}
+
+// askPlayers collects the turn order. Non-interactively it parses USER_MAPPINGS-style input;
+// interactively it asks for one player at a time and checks the list after each entry.
+func (w *wizard) askPlayers(raw string) ([]userparser.Player, error) {
+	if !w.interactive {
+		if strings.TrimSpace(raw) == "" {
+			return nil, errNoAnswer
+		}
+		mappings, err := userparser.ParseUsersFromString(raw)
+		if err != nil {
+			return nil, err
+		}
+		players := make([]userparser.Player, 0, len(mappings))
+		for _, m := range mappings {
+			players = append(players, userparser.Player{Order: m.Order, Name: m.Username, DiscordID: m.DiscordID, Aliases: m.Aliases})
+		}
+		return players, nil
+	}
+
+	fmt.Println("👥 Enter the players in turn order. Leave the name empty when done.")
+	var players []userparser.Player
+	for {
+		name, err := w.prompt(fmt.Sprintf("Player %d name", len(players)+1), "")
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			if len(players) == 0 {
+				fmt.Println("⚠️ Add at least one player")
+				continue
+			}
+			break
+		}
+		id, err := w.ask(fmt.Sprintf("Discord user ID for %s", name), "", userparser.ValidateSnowflake)
+		if err != nil {
+			return nil, err
+		}
+		candidate := append(players, userparser.Player{Order: len(players) + 1, Name: name, DiscordID: id})
+		if _, err := userparser.PlayersToMappings(candidate); err != nil {
+			fmt.Printf("⚠️ %v\n", err)
+			continue
+		}
+		players = candidate
+	}
+	if len(players) < 2 {
+		fmt.Println("⚠️ Only one player entered; add the others before the game starts")
+	}
+	return players, nil
+}
+
+// renderSetupEnv renders the answers as .env lines
+func renderSetupEnv(values map[string]string, players []userparser.Player) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "GAME_NAME=%s\n", values["GAME_NAME"])
+	if values["GAME_PROFILE"] != game.DefaultProfile {
+		fmt.Fprintf(&b, "GAME_PROFILE=%s\n", values["GAME_PROFILE"])
+	}
+	if values["ALLOWED_EXTENSIONS"] != "" {
+		fmt.Fprintf(&b, "ALLOWED_EXTENSIONS=%s\n", values["ALLOWED_EXTENSIONS"])
+	}
+	fmt.Fprintf(&b, "WATCH_DIRECTORY=%s\n", values["WATCH_DIRECTORY"])
+	fmt.Fprintf(&b, "DISCORD_WEBHOOK_URL=%s\n", values["DISCORD_WEBHOOK_URL"])
+	fmt.Fprintf(&b, "USER_MAPPINGS=%s\n", formatUserMappings(players))
+	return b.String()
+}
+
+// renderSetupYAML renders the answers as a config file
+func renderSetupYAML(values map[string]string, players []userparser.Player) (string, error) {
+	f := setupFile{
+		GameName:          values["GAME_NAME"],
+		AllowedExtensions: values["ALLOWED_EXTENSIONS"],
+		WatchDirectory:    values["WATCH_DIRECTORY"],
+		DiscordWebhookURL: values["DISCORD_WEBHOOK_URL"],
+		Players:           players,
+	}
+	if values["GAME_PROFILE"] != game.DefaultProfile {
+		f.GameProfile = values["GAME_PROFILE"]
+	}
+	var b strings.Builder
+	b.WriteString("# Written by the setup wizard\n")
+	enc := yaml.NewEncoder(&b)
+	enc.SetIndent(2)
+	if err := enc.Encode(f); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
@@ -1,5 +1,13 @@
 module github.com/1Solon/shadow-empire-pbem-bot
 
-go 1.24
+go 1.24.0
 
-require github.com/joho/godotenv v1.5.1
+require (
+	github.com/fsnotify/fsnotify v1.9.0
+	github.com/joho/godotenv v1.5.1
+	golang.org/x/net v0.46.0
+	golang.org/x/text v0.30.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require golang.org/x/sys v0.37.0 // indirect
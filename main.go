@@ -2,16 +2,28 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"os/signal"
 	"path/filepath"
-	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
+	_ "time/tzdata" // embed time zone database for player time zones in distroless images
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/health"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/server"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/supervisor"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
 	"github.com/joho/godotenv"
 )
 
@@ -19,77 +31,156 @@ func main() {
 	// Set log output with timestamps
 	log.SetFlags(log.LstdFlags)
 
+	// Dispatch subcommands; without one the bot runs
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "init":
+			os.Exit(runInit(os.Args[2:]))
+		case "doctor":
+			os.Exit(runDoctor(os.Args[2:]))
+		case "healthcheck":
+			os.Exit(runHealthcheck(os.Args[2:]))
+		case "setup":
+			os.Exit(runSetup(os.Args[2:]))
+		case "config":
+			os.Exit(runConfig(os.Args[2:]))
+		}
+	}
+
+	os.Exit(runBot(os.Args[1:]))
+}
+
+// loadEnv loads a .env file from the working directory unless the required variables are already set.
+// Status messages go to out so subcommands can keep stdout clean.
+func loadEnv(out io.Writer) {
+	// A config file may provide the required settings, so .env is optional alongside it
+	if os.Getenv("CONFIG_FILE") != "" {
+		if err := applyDotEnv(); err == nil {
+			fmt.Fprintln(out, "📝 Loading environment variables from .env file")
+		}
+		return
+	}
+
 	// Check if required environment variables exist
-	if os.Getenv("USER_MAPPINGS") == "" || os.Getenv("GAME_NAME") == "" {
+	if os.Getenv("GAMES") == "" && ((os.Getenv("USER_MAPPINGS") == "" && os.Getenv("ROSTER_FILE") == "") || os.Getenv("GAME_NAME") == "") {
 		// If not, try to load from .env file
 		envPath := filepath.Join(".", ".env")
 		if _, err := os.Stat(envPath); err == nil {
-			fmt.Println("📝 Loading environment variables from .env file")
-			err := godotenv.Load()
+			fmt.Fprintln(out, "📝 Loading environment variables from .env file")
+			err := applyDotEnv()
 			if err != nil {
 				log.Printf("⚠️ Error loading .env file: %v", err)
 			}
 		} else {
-			fmt.Println("⚠️ No .env file found and required environment variables not set")
+			fmt.Fprintln(out, "⚠️ No .env file found and required environment variables not set")
 		}
 	} else {
-		fmt.Println("🔧 Using environment variables from system")
+		fmt.Fprintln(out, "🔧 Using environment variables from system")
 	}
+}
 
-	// Load config once
-	cfg := types.LoadConfigFromEnv()
+// dotEnvKeys are the variables set from .env, which a reload may replace or clear
+var dotEnvKeys map[string]bool
 
-	// Check if specific environment variables are set after potential loading
-	if cfg.UserMappingsRaw == "" {
-		fmt.Println("⚠️ USER_MAPPINGS environment variable is not set, exiting")
-		os.Exit(1)
+// applyDotEnv sets the variables from .env that the environment doesn't already set. Variables
+// it set before are replaced, or cleared when .env no longer lists them.
+func applyDotEnv() error {
+	values, err := godotenv.Read()
+	if err != nil {
+		return err
 	}
-	if os.Getenv("GAME_NAME") == "" { // note: cfg has default applied, call out only when env was empty
-		fmt.Println("ℹ️ GAME_NAME environment variable is not set, using default: pbem1")
+	for key := range dotEnvKeys {
+		if _, ok := values[key]; !ok {
+			os.Unsetenv(key)
+		}
 	}
-	if cfg.WebhookURL == "" {
-		fmt.Println("⚠️ DISCORD_WEBHOOK_URL environment variable is not set, webhook notifications will fail")
+	applied := make(map[string]bool, len(values))
+	for key, value := range values {
+		if _, set := os.LookupEnv(key); set && !dotEnvKeys[key] {
+			continue
+		}
+		os.Setenv(key, value)
+		applied[key] = true
 	}
+	dotEnvKeys = applied
+	return nil
+}
 
-	// Check if WATCH_DIRECTORY is set
-	if os.Getenv("WATCH_DIRECTORY") == "" {
-		fmt.Println("⚠️ WATCH_DIRECTORY environment variable is not set, using default: ./data")
+// configFlags registers --config and a flag for every setting on fs. After fs.Parse, the
+// returned function loads .env as needed, reporting to out, and returns the options for types.Load.
+func configFlags(fs *flag.FlagSet, out io.Writer) func() types.LoadOptions {
+	configFile := fs.String("config", "", "path to a YAML config file (or set CONFIG_FILE)")
+	setFlags := types.RegisterFlags(fs)
+	return func() types.LoadOptions {
+		if *configFile != "" {
+			os.Setenv("CONFIG_FILE", *configFile)
+		}
+		loadEnv(out)
+		path := os.Getenv("CONFIG_FILE")
+		if path != "" {
+			fmt.Fprintf(out, "📄 Loading config file: %s\n", path)
+		}
+		return types.LoadOptions{ConfigFile: path, Flags: setFlags()}
 	}
+}
+
+// runBot loads the configuration and monitors every game's watch directory until a shutdown signal arrives.
+// It returns the process exit code.
+func runBot(args []string) int {
+	fs := flag.NewFlagSet("shadow-empire-pbem-bot", flag.ExitOnError)
+	options := configFlags(fs, os.Stdout)
+	fs.Parse(args)
+
+	// Keep secrets out of everything printed from here on
+	restore := redact.CaptureStdout()
+	defer restore()
+	log.SetOutput(redact.NewWriter(os.Stderr))
 
-	// Check if IGNORE_PATTERNS is set
-	if cfg.IgnorePatternsRaw != "" {
-		fmt.Printf("🔍 Will ignore files containing patterns: %s\n", cfg.IgnorePatternsRaw)
+	// Load config once; GAMES selects multi-game mode
+	loadOpts := options()
+	games, err := types.Load(loadOpts)
+	if err != nil {
+		fmt.Printf("⚠️ %v, exiting\n", err)
+		return 1
 	}
+	cfg := games[0]
 
-	// Check if FILE_DEBOUNCE_MS is set
-	if os.Getenv("FILE_DEBOUNCE_MS") == "" {
-		fmt.Println("ℹ️ FILE_DEBOUNCE_MS environment variable is not set, using default: 30000 (30 seconds)")
-	} else {
-		// Convert ms to seconds for display
-		if ms, err := strconv.Atoi(os.Getenv("FILE_DEBOUNCE_MS")); err == nil {
-			fmt.Printf("⏱️ File debounce time set to %d seconds\n", ms/1000)
+	if len(games) > 1 {
+		fmt.Printf("🎲 Hosting %d games in one process\n", len(games))
+	}
+	for _, g := range games {
+		logSources(g)
+	}
+
+	// Check each game's settings after potential loading
+	for i, g := range games {
+		if !checkGameConfig(g) {
+			return 1
+		}
+		for _, other := range games[:i] {
+			if types.SameDirectory(g.WatchDirectory, other.WatchDirectory) && strings.EqualFold(g.GameName, other.GameName) {
+				fmt.Printf("⚠️ Games %s and %s share directory %s and game name %s, exiting\n", other.ID, g.ID, g.WatchDirectory, g.GameName)
+				return 1
+			}
 		}
 	}
 
-	// Check if REMINDER_INTERVAL_MINUTES is set
-	if os.Getenv("REMINDER_INTERVAL_MINUTES") == "" {
-		fmt.Println("ℹ️ REMINDER_INTERVAL_MINUTES environment variable is not set, using default: 720 minutes (12 hours)")
-	} else {
-		minutes, _ := strconv.Atoi(os.Getenv("REMINDER_INTERVAL_MINUTES"))
-		hours := minutes / 60
-		mins := minutes % 60
-
-		if hours > 0 && mins > 0 {
-			fmt.Printf("⏰ Reminder interval set to %d hours and %d minutes\n", hours, mins)
-		} else if hours > 0 {
-			fmt.Printf("⏰ Reminder interval set to %d hours\n", hours)
-		} else {
-			fmt.Printf("⏰ Reminder interval set to %d minutes\n", mins)
+	for _, g := range games {
+		// Check if IGNORE_PATTERNS is set
+		if g.IgnorePatternsRaw != "" {
+			fmt.Printf("🔍 %sWill ignore files containing patterns: %s\n", g.Label(), g.IgnorePatternsRaw)
+		}
+
+		// Show the reminder interval
+		if !g.Sources["REMINDER_INTERVAL_MINUTES"].IsDefault() {
+			fmt.Printf("⏰ %sReminder interval set to %s\n", g.Label(), duration.Format(g.ReminderInterval))
 		}
 	}
 
-	// Start monitoring the directory
-	fmt.Printf("👀 Monitoring directory: %s (poll every %ds)\n", cfg.WatchDirectory, cfg.PollIntervalSec)
+	// Start monitoring the directories
+	for _, g := range games {
+		fmt.Printf("👀 %sMonitoring directory: %s (poll every %s)\n", g.Label(), g.WatchDirectory, duration.Format(g.PollInterval))
+	}
 
 	// Block and monitor directory with graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
@@ -105,5 +196,128 @@ func main() {
 		cancel()
 	}()
 
-	monitor.MonitorDirectory(ctx, cfg)
+	// Serve status for all games when enabled
+	if cfg.HTTPAddr != "" {
+		server.Start(ctx, cfg.HTTPAddr)
+	}
+
+	// Share each game's configuration with its monitor so reloads can update it
+	lives := make([]*types.LiveConfig, len(games))
+	for i, g := range games {
+		lives[i] = types.NewLiveConfig(g)
+	}
+
+	// Record heartbeats for the health endpoints and the healthcheck subcommand
+	for _, g := range games {
+		health.Register(g.ID, g.WatchDirectory, g.PollInterval)
+	}
+	go writeHeartbeats(ctx, heartbeatFile())
+
+	// Reload on SIGHUP or when the config file changes
+	r := newReloader(loadOpts, lives)
+	go r.run(ctx)
+
+	// Run each game in its own supervised goroutine with isolated state
+	var wg sync.WaitGroup
+	var stopped atomic.Bool
+	for _, live := range lives {
+		wg.Add(1)
+		go func(live *types.LiveConfig) {
+			defer wg.Done()
+			name := "monitor for game " + live.Get().GameName
+			err := supervisor.Run(ctx, name, func(ctx context.Context) error {
+				return monitor.MonitorDirectory(ctx, live)
+			}, supervisor.Options{OnCrash: func(c supervisor.Crash) { reportCrash(c, live.Get()) }})
+			if err != nil {
+				stopped.Store(true)
+			}
+		}(live)
+	}
+	wg.Wait()
+	if stopped.Load() {
+		return 1
+	}
+	return 0
+}
+
+// logSources prints the effective value of every setting and where it came from, hiding secrets
+func logSources(cfg types.Config) {
+	for _, s := range types.Settings {
+		value := cfg.Setting(s.Key)
+		switch {
+		case value == "":
+			value = "(not set)"
+		case s.Secret:
+			value = "(hidden)"
+		}
+		fmt.Printf("⚙️ %s%s = %s [%s]\n", cfg.Label(), s.Key, value, cfg.Sources[s.Key])
+	}
+	if len(cfg.Players) > 0 {
+		fmt.Printf("⚙️ %s%d players from the config file\n", cfg.Label(), len(cfg.Players))
+	}
+}
+
+// reportCrash sends a supervised monitor failure to the admin channel. Repeated failures are
+// reported on the first attempt and every tenth one after that to avoid flooding the channel.
+func reportCrash(c supervisor.Crash, cfg types.Config) {
+	if !c.Final && c.Attempt != 1 && c.Attempt%10 != 0 {
+		return
+	}
+
+	// Mention roster admins if the roster can be read
+	var adminIDs []string
+	if users, _, err := cfg.LoadPlayers(); err == nil {
+		for _, u := range users {
+			if u.Admin {
+				adminIDs = append(adminIDs, u.DiscordID)
+			}
+		}
+	}
+
+	details := fmt.Sprintf("%v\n\nRestarting in %v (attempt %d)", c.Err, c.Backoff, c.Attempt)
+	if c.Final {
+		details = fmt.Sprintf("%v\n\nNot restarting; fix the problem and restart the bot", c.Err)
+	}
+	if c.Stack != nil {
+		details += "\n\n" + string(c.Stack)
+	}
+	title := fmt.Sprintf("The %s crashed", c.Name)
+	if c.Final {
+		title = fmt.Sprintf("The %s stopped", c.Name)
+	}
+	if err := webhook.SendAdminAlertWebHook(title, details, adminIDs, cfg); err != nil {
+		log.Printf("❌ Failed to send crash report: %v\n", err)
+	}
+}
+
+// checkGameConfig reports problems with a single game's settings and returns false if the game cannot run
+func checkGameConfig(cfg types.Config) bool {
+	label := cfg.Label()
+	if err := cfg.Validate(); err != nil {
+		var verr *types.ValidationError
+		if !errors.As(err, &verr) {
+			fmt.Printf("⚠️ %s%v\n", label, err)
+			return false
+		}
+		for _, p := range verr.Problems {
+			if p.Fatal {
+				fmt.Printf("❌ %s%s\n", label, p)
+			} else {
+				fmt.Printf("⚠️ %s%s\n", label, p)
+			}
+		}
+		if verr.Fatal() {
+			fmt.Printf("🛑 %sRefusing to start until the configuration problems above are fixed\n", label)
+			return false
+		}
+	}
+
+	if cfg.RosterFile != "" {
+		fmt.Printf("📇 %sLoading players from roster file: %s\n", label, cfg.RosterFile)
+	}
+	fmt.Printf("🎮 %sUsing game profile: %s (extensions: %s)\n", label, cfg.Profile().Title, strings.Join(cfg.AllowedExtensions, ", "))
+	if cfg.AutoRename != types.AutoRenameOff {
+		fmt.Printf("🛠️ %sMisnamed saves will be fixed automatically (AUTO_RENAME=%s)\n", label, cfg.AutoRename)
+	}
+	return true
 }
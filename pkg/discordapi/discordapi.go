@@ -0,0 +1,156 @@
+package discordapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Discord REST API root used when no override is configured.
+const DefaultBaseURL = "https://discord.com/api/v10"
+
+// ErrUnknownUser is returned when Discord reports that a user ID does not exist.
+var ErrUnknownUser = errors.New("unknown Discord user")
+
+// ErrNotMember is returned when a user is not a member of the configured guild.
+var ErrNotMember = errors.New("not a member of the Discord server")
+
+// User is the subset of the Discord user object the bot cares about.
+type User struct {
+	ID         string `json:"id"`
+	Username   string `json:"username"`
+	GlobalName string `json:"global_name"`
+}
+
+// DisplayName returns the global display name, falling back to the account username.
+func (u User) DisplayName() string {
+	if u.GlobalName != "" {
+		return u.GlobalName
+	}
+	return u.Username
+}
+
+// Member is the subset of the Discord guild member object the bot cares about.
+type Member struct {
+	User User   `json:"user"`
+	Nick string `json:"nick"`
+}
+
+// DisplayName returns the member's server nickname, falling back to the user's display name.
+func (m Member) DisplayName() string {
+	if m.Nick != "" {
+		return m.Nick
+	}
+	return m.User.DisplayName()
+}
+
+// Client is a minimal Discord REST client authenticated with a bot token.
+// BaseURL can point at a local stand-in server.
+type Client struct {
+	BaseURL string
+	Token   string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for the given bot token. An empty baseURL selects DefaultBaseURL.
+func NewClient(baseURL, token string) *Client {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Token:   token,
+		HTTP:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// GetUser fetches a user by ID. It returns ErrUnknownUser if the ID does not exist.
+func (c *Client) GetUser(ctx context.Context, id string) (*User, error) {
+	var u User
+	if err := c.get(ctx, "/users/"+id, "user "+id, ErrUnknownUser, &u); err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
+
+// GetMember fetches a user's membership in a guild (Discord server). It returns ErrNotMember
+// if the user is not in the guild or does not exist.
+func (c *Client) GetMember(ctx context.Context, guildID, userID string) (*Member, error) {
+	var m Member
+	if err := c.get(ctx, "/guilds/"+guildID+"/members/"+userID, "member "+userID, ErrNotMember, &m); err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// LookupDisplayName returns a user's nickname in the guild when guildID is set, or their
+// global display name otherwise.
+func (c *Client) LookupDisplayName(ctx context.Context, guildID, userID string) (string, error) {
+	if guildID != "" {
+		m, err := c.GetMember(ctx, guildID, userID)
+		if err != nil {
+			return "", err
+		}
+		return m.DisplayName(), nil
+	}
+	u, err := c.GetUser(ctx, userID)
+	if err != nil {
+		return "", err
+	}
+	return u.DisplayName(), nil
+}
+
+// get requests path and decodes the JSON response into out. A 404 is returned as notFound;
+// what describes the resource in other errors.
+func (c *Client) get(ctx context.Context, path, what string, notFound error, out any) error {
+	// Retry once on rate limiting, honoring Retry-After
+	for attempt := 1; attempt <= 2; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.BaseURL+path, nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bot "+c.Token)
+
+		resp, err := c.HTTP.Do(req)
+		if err != nil {
+			return fmt.Errorf("requesting Discord %s: %w", what, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+
+		switch resp.StatusCode {
+		case http.StatusOK:
+			if err := json.Unmarshal(body, out); err != nil {
+				return fmt.Errorf("decoding Discord %s: %w", what, err)
+			}
+			return nil
+		case http.StatusNotFound:
+			return notFound
+		case http.StatusUnauthorized:
+			return fmt.Errorf("discord rejected the bot token (401)")
+		case http.StatusTooManyRequests:
+			if attempt == 1 {
+				wait := time.Second
+				if secs, err := strconv.ParseFloat(resp.Header.Get("Retry-After"), 64); err == nil {
+					wait = time.Duration(secs * float64(time.Second))
+				}
+				select {
+				case <-time.After(wait):
+					continue
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			return fmt.Errorf("discord rate limit exceeded looking up %s", what)
+		default:
+			return fmt.Errorf("discord returned status %d for %s: %s", resp.StatusCode, what, string(body))
+		}
+	}
+	return fmt.Errorf("failed to look up Discord %s", what)
+}
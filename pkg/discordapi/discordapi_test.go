@@ -0,0 +1,192 @@
+package discordapi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	testToken = "test-token"
+	guildID   = "400000000000000000"
+	aliceID   = "123456789012345678"
+	bobID     = "223456789012345678"
+)
+
+// newStandIn serves the user and guild member endpoints for two known users, answering the
+// first limited authorized requests with 429. It also returns the number of requests served.
+func newStandIn(t *testing.T, limited int32) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	var throttled atomic.Int32
+	users := map[string]string{
+		aliceID: `{"id":"` + aliceID + `","username":"alice","global_name":"Alice Liddell"}`,
+		bobID:   `{"id":"` + bobID + `","username":"bob","global_name":""}`,
+	}
+	members := map[string]string{
+		aliceID: `{"user":` + users[aliceID] + `,"nick":"Queen Alice"}`,
+		bobID:   `{"user":` + users[bobID] + `,"nick":null}`,
+	}
+	reply := func(w http.ResponseWriter, r *http.Request, objects map[string]string, id string) {
+		requests.Add(1)
+		if r.Header.Get("Authorization") != "Bot "+testToken {
+			http.Error(w, `{"message":"401: Unauthorized","code":0}`, http.StatusUnauthorized)
+			return
+		}
+		if throttled.Add(1) <= limited {
+			w.Header().Set("Retry-After", "0.01")
+			http.Error(w, `{"message":"You are being rate limited.","retry_after":0.01}`, http.StatusTooManyRequests)
+			return
+		}
+		obj, ok := objects[id]
+		if !ok {
+			http.Error(w, `{"message":"Unknown","code":10013}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(obj))
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /users/{id}", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, r, users, r.PathValue("id"))
+	})
+	mux.HandleFunc("GET /guilds/{guild}/members/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("guild") != guildID {
+			requests.Add(1)
+			http.Error(w, `{"message":"Unknown Guild","code":10004}`, http.StatusNotFound)
+			return
+		}
+		reply(w, r, members, r.PathValue("id"))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &requests
+}
+
+func TestGetUser(t *testing.T) {
+	srv, _ := newStandIn(t, 0)
+	c := NewClient(srv.URL+"/", testToken)
+
+	u, err := c.GetUser(context.Background(), aliceID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if u.ID != aliceID || u.DisplayName() != "Alice Liddell" {
+		t.Errorf("GetUser = %+v, display name %q", u, u.DisplayName())
+	}
+
+	u, err = c.GetUser(context.Background(), bobID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if u.DisplayName() != "bob" {
+		t.Errorf("DisplayName without a global name = %q, want the username", u.DisplayName())
+	}
+
+	if _, err := c.GetUser(context.Background(), "323456789012345678"); !errors.Is(err, ErrUnknownUser) {
+		t.Errorf("GetUser of a missing user: err = %v, want ErrUnknownUser", err)
+	}
+
+	bad := NewClient(srv.URL, "wrong")
+	if _, err := bad.GetUser(context.Background(), aliceID); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("GetUser with a bad token: err = %v, want a 401 error", err)
+	}
+}
+
+func TestGetMember(t *testing.T) {
+	srv, _ := newStandIn(t, 0)
+	c := NewClient(srv.URL, testToken)
+
+	tests := []struct {
+		guild, user string
+		want        string
+		wantErr     error
+	}{
+		{guildID, aliceID, "Queen Alice", nil},
+		{guildID, bobID, "bob", nil},
+		{guildID, "323456789012345678", "", ErrNotMember},
+		{"500000000000000000", aliceID, "", ErrNotMember},
+	}
+	for _, tt := range tests {
+		m, err := c.GetMember(context.Background(), tt.guild, tt.user)
+		if tt.wantErr != nil {
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetMember(%s, %s): err = %v, want %v", tt.guild, tt.user, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("GetMember(%s, %s): %v", tt.guild, tt.user, err)
+			continue
+		}
+		if m.DisplayName() != tt.want {
+			t.Errorf("GetMember(%s, %s).DisplayName() = %q, want %q", tt.guild, tt.user, m.DisplayName(), tt.want)
+		}
+	}
+}
+
+func TestLookupDisplayName(t *testing.T) {
+	srv, _ := newStandIn(t, 0)
+	c := NewClient(srv.URL, testToken)
+
+	if name, err := c.LookupDisplayName(context.Background(), "", aliceID); err != nil || name != "Alice Liddell" {
+		t.Errorf("LookupDisplayName without a guild = %q, %v", name, err)
+	}
+	if name, err := c.LookupDisplayName(context.Background(), guildID, aliceID); err != nil || name != "Queen Alice" {
+		t.Errorf("LookupDisplayName in a guild = %q, %v", name, err)
+	}
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	srv, requests := newStandIn(t, 1)
+	c := NewClient(srv.URL, testToken)
+
+	u, err := c.GetUser(context.Background(), aliceID)
+	if err != nil {
+		t.Fatalf("GetUser after one 429: %v", err)
+	}
+	if u.Username != "alice" {
+		t.Errorf("GetUser = %+v", u)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestRateLimitGivesUp(t *testing.T) {
+	srv, requests := newStandIn(t, 5)
+	c := NewClient(srv.URL, testToken)
+
+	_, err := c.GetMember(context.Background(), guildID, aliceID)
+	if err == nil || !strings.Contains(err.Error(), "rate limit") {
+		t.Errorf("GetMember while rate limited: err = %v, want a rate limit error", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("made %d requests, want 2", n)
+	}
+}
+
+func TestRateLimitCanceled(t *testing.T) {
+	limited := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() { limited <- struct{}{} }()
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+	c := NewClient(srv.URL, testToken)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		<-limited
+		cancel()
+	}()
+	if _, err := c.GetUser(ctx, aliceID); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetUser canceled during Retry-After: err = %v, want context.Canceled", err)
+	}
+}
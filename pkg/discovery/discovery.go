@@ -0,0 +1,174 @@
+package discovery
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// Player is a player name found in save filenames.
+type Player struct {
+	Order     int
+	Name      string
+	Saves     int
+	FirstTurn int
+	LastSeen  time.Time
+}
+
+// Result describes the game inferred from the saves in a directory.
+type Result struct {
+	GameName string
+	Players  []Player
+	// Games counts the saves found per game prefix, including ones that were not chosen.
+	Games map[string]int
+	// Unparsed lists save candidates that did not match the naming grammar.
+	Unparsed []string
+	// Extensions lists the file extensions of the chosen game's saves, without dots.
+	Extensions []string
+}
+
+type save struct {
+	game    string
+	player  string
+	turn    int
+	ext     string
+	modTime time.Time
+}
+
+// Scan inspects the saves in dir and infers the game prefix, the player names and their
+// turn order. Saves are ordered by turn number and then by modification time, and the order
+// in which player names first appear is taken as the rotation. Since each save is named for
+// the player whose turn it is, the first player has no save on turn 1; the rotation starts
+// with the player named in the first save of a later turn, or with the first name seen if
+// all saves are from one turn.
+func Scan(dir string, profile game.Profile, extensions []string) (*Result, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", dir, err)
+	}
+
+	res := &Result{Games: make(map[string]int)}
+	var saves []save
+	for _, e := range entries {
+		if e.IsDir() || !isCandidate(e.Name(), profile, extensions) {
+			continue
+		}
+		sn, ok := profile.ParseSaveName(e.Name())
+		if !ok {
+			res.Unparsed = append(res.Unparsed, e.Name())
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			continue
+		}
+		// Keep the original spelling of the name parts, the grammar works on lowercase
+		base := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		saves = append(saves, save{
+			game:    originalCase(base, sn.Game),
+			player:  originalCase(base, sn.Player),
+			turn:    sn.Turn,
+			ext:     strings.ToLower(strings.TrimPrefix(filepath.Ext(e.Name()), ".")),
+			modTime: fi.ModTime(),
+		})
+		res.Games[originalCase(base, sn.Game)]++
+	}
+
+	if len(saves) == 0 {
+		return res, nil
+	}
+
+	res.GameName = mostCommonGame(res.Games)
+
+	sort.SliceStable(saves, func(i, j int) bool {
+		if saves[i].turn != saves[j].turn {
+			return saves[i].turn < saves[j].turn
+		}
+		return saves[i].modTime.Before(saves[j].modTime)
+	})
+
+	byName := make(map[string]int)
+	start, lowestTurn := -1, -1
+	for _, s := range saves {
+		if !strings.EqualFold(s.game, res.GameName) {
+			continue
+		}
+		if lowestTurn == -1 {
+			lowestTurn = s.turn
+		}
+		if s.ext != "" && !slices.Contains(res.Extensions, s.ext) {
+			res.Extensions = append(res.Extensions, s.ext)
+		}
+		key := userparser.FoldName(s.player)
+		idx, ok := byName[key]
+		if !ok {
+			res.Players = append(res.Players, Player{Name: s.player, FirstTurn: s.turn})
+			idx = len(res.Players) - 1
+			byName[key] = idx
+		}
+		if start == -1 && s.turn > lowestTurn {
+			start = idx
+		}
+		p := &res.Players[idx]
+		p.Saves++
+		if s.modTime.After(p.LastSeen) {
+			p.LastSeen = s.modTime
+		}
+	}
+	if start > 0 {
+		res.Players = slices.Concat(res.Players[start:], res.Players[:start])
+	}
+	for i := range res.Players {
+		res.Players[i].Order = i + 1
+	}
+	sort.Strings(res.Extensions)
+	return res, nil
+}
+
+// mostCommonGame returns the prefix with the most saves, preferring the alphabetically
+// first one on ties so the result is stable.
+func mostCommonGame(games map[string]int) string {
+	best, bestCount := "", 0
+	for g, n := range games {
+		if n > bestCount || (n == bestCount && g < best) {
+			best, bestCount = g, n
+		}
+	}
+	return best
+}
+
+// originalCase finds part (lowercase) inside name and returns it with name's original casing.
+func originalCase(name, part string) string {
+	if i := strings.Index(strings.ToLower(name), part); i >= 0 && len(strings.ToLower(name)) == len(name) {
+		return name[i : i+len(part)]
+	}
+	return part
+}
+
+func isCandidate(name string, profile game.Profile, extensions []string) bool {
+	lower := strings.ToLower(name)
+	if profile.IsTemp(lower) || profile.IsAutosave(lower) {
+		return false
+	}
+	for _, r := range userparser.RosterFileNames {
+		if lower == r {
+			return false
+		}
+	}
+	if len(extensions) == 0 {
+		return true
+	}
+	for _, e := range extensions {
+		if strings.HasSuffix(lower, "."+strings.TrimPrefix(e, ".")) {
+			return true
+		}
+	}
+	return false
+}
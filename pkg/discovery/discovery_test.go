@@ -0,0 +1,160 @@
+package discovery
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+)
+
+// writeSaves creates the files in a temporary directory, each a minute after the one before
+func writeSaves(t *testing.T, names ...string) string {
+	t.Helper()
+	dir := t.TempDir()
+	modTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte("save"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+		modTime = modTime.Add(time.Minute)
+	}
+	return dir
+}
+
+func playerNames(res *Result) []string {
+	var names []string
+	for i, p := range res.Players {
+		if p.Order != i+1 {
+			return nil
+		}
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestScanOrder(t *testing.T) {
+	tests := []struct {
+		name  string
+		saves []string
+		want  []string
+	}{
+		{
+			"first round",
+			[]string{"PBEM1_turn1_Bob.se1", "PBEM1_turn1_Carol.se1", "PBEM1_turn2_Alice.se1"},
+			[]string{"Alice", "Bob", "Carol"},
+		},
+		{
+			"several rounds",
+			[]string{
+				"PBEM1_turn1_Bob.se1", "PBEM1_turn1_Carol.se1",
+				"PBEM1_turn2_Alice.se1", "PBEM1_turn2_Bob.se1", "PBEM1_turn2_Carol.se1",
+				"PBEM1_turn3_Alice.se1",
+			},
+			[]string{"Alice", "Bob", "Carol"},
+		},
+		{
+			"old rounds cleaned up",
+			[]string{"PBEM1_turn7_Carol.se1", "PBEM1_turn8_Alice.se1", "PBEM1_turn8_Bob.se1"},
+			[]string{"Alice", "Bob", "Carol"},
+		},
+		{
+			"full round without the next one",
+			[]string{"PBEM1_turn4_Alice.se1", "PBEM1_turn4_Bob.se1", "PBEM1_turn4_Carol.se1"},
+			[]string{"Alice", "Bob", "Carol"},
+		},
+		{
+			// The first player has not been named in a save yet
+			"game just started",
+			[]string{"PBEM1_turn1_Bob.se1", "PBEM1_turn1_Carol.se1"},
+			[]string{"Bob", "Carol"},
+		},
+		{
+			"player before turn",
+			[]string{"PBEM1_Bob_turn1.se1", "PBEM1_Carol_turn1.se1", "PBEM1_Alice_turn2.se1", "PBEM1_Bob_turn2.se1"},
+			[]string{"Alice", "Bob", "Carol"},
+		},
+		{
+			// Turn numbers win over modification times, e.g. after copying saves around
+			"modification times out of order",
+			[]string{"PBEM1_turn2_Alice.se1", "PBEM1_turn1_Carol.se1", "PBEM1_turn1_Bob.se1", "PBEM1_turn2_Bob.se1"},
+			[]string{"Alice", "Carol", "Bob"},
+		},
+	}
+	for _, tt := range tests {
+		res, err := Scan(writeSaves(t, tt.saves...), game.Default(), []string{"se1"})
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got := playerNames(res); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: players = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestScanDirectory(t *testing.T) {
+	dir := writeSaves(t,
+		"Campaign_turn1_Bob.se1",
+		"Campaign_turn1_Jörg.se1",
+		"Campaign_turn2_Alice.se1",
+		"Campaign_turn2_Bob.SE1",
+		"campaign_turn2_jörg.se1",      // same player in another case
+		"Campaign_turn2_Joerg.se1.tmp", // being written
+		"autosave_turn3.se1",
+		"Test_turn1_Bob.se1", // another game
+		"notes.txt",
+		"players.yaml",
+		"Campaign-final.se1", // does not follow the naming scheme
+	)
+	if err := os.Mkdir(filepath.Join(dir, "Campaign_turn9_Old.se1"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := Scan(dir, game.Default(), []string{"se1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.GameName != "Campaign" {
+		t.Errorf("GameName = %q, want Campaign", res.GameName)
+	}
+	if res.Games["Campaign"]+res.Games["campaign"] != 5 || res.Games["Test"] != 1 {
+		t.Errorf("Games = %v, want 5 Campaign saves and 1 Test save", res.Games)
+	}
+	if got := playerNames(res); !slices.Equal(got, []string{"Alice", "Bob", "Jörg"}) {
+		t.Errorf("players = %q, want Alice, Bob, Jörg", got)
+	}
+	if len(res.Players) == 3 && (res.Players[1].Saves != 2 || res.Players[2].Saves != 2 || res.Players[2].FirstTurn != 1) {
+		t.Errorf("players = %+v, want two saves each for Bob and Jörg", res.Players)
+	}
+	if !slices.Equal(res.Unparsed, []string{"Campaign-final.se1"}) {
+		t.Errorf("Unparsed = %q, want [Campaign-final.se1]", res.Unparsed)
+	}
+	if !slices.Equal(res.Extensions, []string{"se1"}) {
+		t.Errorf("Extensions = %q, want [se1]", res.Extensions)
+	}
+
+	if _, err := Scan(filepath.Join(dir, "missing"), game.Default(), nil); err == nil {
+		t.Error("Scan of a missing directory succeeded")
+	}
+}
+
+func TestScanGenericExtensions(t *testing.T) {
+	generic, _ := game.Lookup("generic")
+	dir := writeSaves(t, "Duel_turn1_Bob.sav", "Duel_turn2_Alice.SAV", "Duel_turn2_Bob.bak", "readme.md")
+	res, err := Scan(dir, generic, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := playerNames(res); !slices.Equal(got, []string{"Alice", "Bob"}) {
+		t.Errorf("players = %q, want Alice, Bob", got)
+	}
+	if !slices.Equal(res.Extensions, []string{"sav"}) {
+		t.Errorf("Extensions = %q, want [sav]", res.Extensions)
+	}
+}
@@ -0,0 +1,85 @@
+// Package duration parses and formats the bot's timing settings.
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse reads a Go duration such as "30s", "12h" or "1h30m". A plain integer is read
+// in legacyUnit so older settings like FILE_DEBOUNCE_MS=30000 keep working.
+func Parse(s string, legacyUnit time.Duration) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Duration(n) * legacyUnit, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("'%s' is not a duration like 30s, 12h or 1h30m, or a whole number of %s", s, unitName(legacyUnit))
+	}
+	return d, nil
+}
+
+// Format renders d for people, e.g. "1 hour and 30 minutes". Durations under a
+// second are shown in milliseconds.
+func Format(d time.Duration) string {
+	if d < 0 {
+		return "-" + Format(-d)
+	}
+	if d == 0 {
+		return "0 seconds"
+	}
+	if d < time.Second {
+		return plural(int64(d/time.Millisecond), "millisecond")
+	}
+
+	var parts []string
+	for _, u := range []struct {
+		size time.Duration
+		name string
+	}{{time.Hour, "hour"}, {time.Minute, "minute"}, {time.Second, "second"}} {
+		if n := d / u.size; n > 0 {
+			parts = append(parts, plural(int64(n), u.name))
+			d -= n * u.size
+		}
+	}
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	return strings.Join(parts[:len(parts)-1], ", ") + " and " + parts[len(parts)-1]
+}
+
+// Short renders d compactly in a form Parse accepts, e.g. "1h30m" or "30s".
+func Short(d time.Duration) string {
+	s := d.String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+func plural(n int64, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+func unitName(unit time.Duration) string {
+	switch unit {
+	case time.Millisecond:
+		return "milliseconds"
+	case time.Second:
+		return "seconds"
+	case time.Minute:
+		return "minutes"
+	case time.Hour:
+		return "hours"
+	}
+	return unit.String() + " units"
+}
@@ -0,0 +1,104 @@
+package duration
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		unit    time.Duration
+		want    time.Duration
+		wantErr string
+	}{
+		// Legacy integers in each unit
+		{"30000", time.Millisecond, 30 * time.Second, ""},
+		{"60", time.Second, time.Minute, ""},
+		{"360", time.Minute, 6 * time.Hour, ""},
+		{"2", time.Hour, 2 * time.Hour, ""},
+		{" 15 ", time.Minute, 15 * time.Minute, ""},
+		{"0", time.Minute, 0, ""},
+		// Go duration strings, whatever the legacy unit
+		{"30s", time.Millisecond, 30 * time.Second, ""},
+		{"12h", time.Minute, 12 * time.Hour, ""},
+		{"1h30m", time.Second, 90 * time.Minute, ""},
+		{"1.5h", time.Minute, 90 * time.Minute, ""},
+		{"250ms", time.Second, 250 * time.Millisecond, ""},
+		// Negatives are parsed; Validate rejects them where they make no sense
+		{"-5", time.Minute, -5 * time.Minute, ""},
+		{"-30s", time.Minute, -30 * time.Second, ""},
+		// Invalid values name the legacy unit
+		{"", time.Minute, 0, "whole number of minutes"},
+		{"12 hours", time.Minute, 0, "'12 hours' is not a duration"},
+		{"1.5", time.Second, 0, "whole number of seconds"},
+		{"30x", time.Millisecond, 0, "whole number of milliseconds"},
+		{"1d", time.Hour, 0, "whole number of hours"},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in, tt.unit)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("Parse(%q, %s) = %v", tt.in, tt.unit, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("Parse(%q, %s) error = %v, want one containing %q", tt.in, tt.unit, err, tt.wantErr)
+		case got != tt.want:
+			t.Errorf("Parse(%q, %s) = %s, want %s", tt.in, tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0 seconds"},
+		{500 * time.Millisecond, "500 milliseconds"},
+		{time.Millisecond, "1 millisecond"},
+		{time.Second, "1 second"},
+		{30 * time.Second, "30 seconds"},
+		{90 * time.Second, "1 minute and 30 seconds"},
+		{6 * time.Hour, "6 hours"},
+		{90 * time.Minute, "1 hour and 30 minutes"},
+		{time.Hour + time.Minute + time.Second, "1 hour, 1 minute and 1 second"},
+		{49 * time.Hour, "49 hours"},
+		{1500 * time.Millisecond, "1 second"},
+		{-90 * time.Minute, "-1 hour and 30 minutes"},
+	}
+	for _, tt := range tests {
+		if got := Format(tt.in); got != tt.want {
+			t.Errorf("Format(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestShort(t *testing.T) {
+	tests := []struct {
+		in   time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{30 * time.Second, "30s"},
+		{5 * time.Minute, "5m"},
+		{90 * time.Minute, "1h30m"},
+		{12 * time.Hour, "12h"},
+		{time.Hour + time.Second, "1h0m1s"},
+		{1500 * time.Millisecond, "1.5s"},
+		{250 * time.Millisecond, "250ms"},
+		{-90 * time.Minute, "-1h30m"},
+	}
+	for _, tt := range tests {
+		got := Short(tt.in)
+		if got != tt.want {
+			t.Errorf("Short(%s) = %q, want %q", tt.in, got, tt.want)
+		}
+		// Whatever the legacy unit, the short form parses back to the same duration
+		for _, unit := range []time.Duration{time.Millisecond, time.Minute} {
+			if back, err := Parse(got, unit); err != nil || back != tt.in {
+				t.Errorf("Parse(Short(%s) = %q, %s) = %s, %v; want the original duration", tt.in, got, unit, back, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,221 @@
+package game
+
+import (
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultProfile is the profile used when GAME_PROFILE is not set.
+const DefaultProfile = "shadow-empire"
+
+// Profile bundles everything that differs between file-based PBEM titles:
+// which files are saves, how they are named and how players are addressed.
+type Profile struct {
+	ID            string
+	Title         string
+	AssistantName string
+	ThumbnailURL  string
+
+	// Extensions lists save file extensions without dots; when empty, ALLOWED_EXTENSIONS is required.
+	Extensions []string
+
+	// SaveNameFormat is the canonical save name, with {game}, {turn} and {player} placeholders.
+	SaveNameFormat string
+
+	// NameGrammar lists the accepted save name layouts. Each expression may capture
+	// "game", "turn" and "player" groups and is matched against the lowercased name
+	// without extension.
+	NameGrammar []*regexp.Regexp
+
+	// TurnPatterns extract the turn number (first group) from a lowercased filename.
+	TurnPatterns []*regexp.Regexp
+
+	// AutosavePatterns and TempPatterns are lowercase glob patterns for files the game
+	// writes on its own; matching files are never treated as turn handoffs.
+	AutosavePatterns []string
+	TempPatterns     []string
+
+	// SaveInstruction introduces the expected save name in turn notifications.
+	SaveInstruction string
+}
+
+// SaveName is the result of parsing a filename with a profile's naming grammar.
+type SaveName struct {
+	Game   string
+	Turn   int
+	Player string
+}
+
+// shadowEmpire is the default profile, and the base for the others
+var shadowEmpire = Profile{
+	ID:             "shadow-empire",
+	Title:          "Shadow Empire",
+	AssistantName:  "Shadow Empire Assistant",
+	ThumbnailURL:   "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
+	Extensions:     []string{"se1"},
+	SaveNameFormat: "{game}_turn{turn}_{player}",
+	NameGrammar: []*regexp.Regexp{
+		regexp.MustCompile(`^(?P<game>[^_]+)_turn(?P<turn>\d+)_?(?P<player>.+)$`),  // PBEM1_turn1_Player
+		regexp.MustCompile(`^(?P<game>[^_]+)_(?P<player>.+?)_?turn(?P<turn>\d+)$`), // PBEM1_Player_turn1
+	},
+	TurnPatterns: []*regexp.Regexp{
+		regexp.MustCompile(`(?:^|_)turn(\d+)(?:_|$)`),         // _turn1_ or _turn1 end
+		regexp.MustCompile(`(?:^|_)player_?turn(\d+)(?:_|$)`), // _player_turn1
+	},
+	AutosavePatterns: []string{"autosave*", "*_autosave*"},
+	TempPatterns:     []string{"*.tmp", "~*"},
+	SaveInstruction:  "After completing your turn, save the file as:",
+}
+
+var profiles = map[string]Profile{
+	shadowEmpire.ID: shadowEmpire,
+	"generic":       generic(),
+}
+
+// generic accepts saves of any PBEM title that follow the Shadow Empire naming scheme
+func generic() Profile {
+	p := shadowEmpire
+	p.ID = "generic"
+	p.Title = "PBEM"
+	p.AssistantName = "PBEM Assistant"
+	p.ThumbnailURL = ""
+	p.Extensions = nil
+	p.TempPatterns = []string{"*.tmp", "*.bak", "~*"}
+	p.SaveInstruction = "After completing your turn, save or export your game as:"
+	return p
+}
+
+// Lookup returns the profile with the given ID (case-insensitive).
+func Lookup(id string) (Profile, bool) {
+	p, ok := profiles[strings.ToLower(strings.TrimSpace(id))]
+	return p, ok
+}
+
+// Default returns the Shadow Empire profile.
+func Default() Profile {
+	return profiles[DefaultProfile]
+}
+
+// Names returns the IDs of all known profiles in sorted order.
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for id := range profiles {
+		names = append(names, id)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// FormatSaveName renders the canonical save name for the given game, turn and player.
+func (p Profile) FormatSaveName(gameName string, turn int, player string) string {
+	return strings.NewReplacer(
+		"{game}", gameName,
+		"{turn}", strconv.Itoa(turn),
+		"{player}", player,
+	).Replace(p.SaveNameFormat)
+}
+
+// ParseSaveName parses a filename using the profile's naming grammar.
+// The extension is ignored and the returned parts are lowercase.
+func (p Profile) ParseSaveName(filename string) (SaveName, bool) {
+	return p.parseBase(baseName(filename))
+}
+
+// baseName lowercases filename and drops its extension
+func baseName(filename string) string {
+	base := strings.ToLower(filename)
+	if ext := path.Ext(base); ext != "" {
+		base = strings.TrimSuffix(base, ext)
+	}
+	return base
+}
+
+// parseBase matches a lowercased name without extension against the naming grammar
+func (p Profile) parseBase(base string) (SaveName, bool) {
+	for _, rx := range p.NameGrammar {
+		m := rx.FindStringSubmatch(base)
+		if m == nil {
+			continue
+		}
+		var sn SaveName
+		for i, name := range rx.SubexpNames() {
+			switch name {
+			case "game":
+				sn.Game = m[i]
+			case "player":
+				sn.Player = strings.Trim(m[i], "_")
+			case "turn":
+				sn.Turn, _ = strconv.Atoi(m[i])
+			}
+		}
+		if sn.Player != "" {
+			return sn, true
+		}
+	}
+	return SaveName{}, false
+}
+
+// gamePlaceholder stands in for the game name while matching it, so names containing
+// underscores or other characters the grammar treats specially still parse as one token
+const gamePlaceholder = "\x00"
+
+// MatchesGame reports whether filename is a save of gameName. Names the grammar can parse
+// must name exactly that game, wherever the grammar puts it; free-form names it cannot
+// parse are accepted when they start with the game name.
+func (p Profile) MatchesGame(filename, gameName string) bool {
+	base := baseName(filename)
+	game := strings.ToLower(gameName)
+	if game == "" {
+		return false
+	}
+	for i := 0; i+len(game) <= len(base); i++ {
+		if !strings.HasPrefix(base[i:], game) {
+			continue
+		}
+		if sn, ok := p.parseBase(base[:i] + gamePlaceholder + base[i+len(game):]); ok && sn.Game == gamePlaceholder {
+			return true
+		}
+	}
+	if _, ok := p.parseBase(base); ok {
+		// A well-formed save of another game
+		return false
+	}
+	return strings.HasPrefix(base, game)
+}
+
+// TurnNumber attempts to extract the turn number from a filename, returning 0 if none is found.
+// The extension is ignored, so a turn at the end of the name is found.
+func (p Profile) TurnNumber(filename string) int {
+	lower := baseName(filename)
+	for _, rx := range p.TurnPatterns {
+		if m := rx.FindStringSubmatch(lower); len(m) > 1 {
+			if n, err := strconv.Atoi(m[1]); err == nil {
+				return n
+			}
+		}
+	}
+	return 0
+}
+
+// IsAutosave reports whether filename is an automatic save written by the game itself.
+func (p Profile) IsAutosave(filename string) bool {
+	return matchAny(p.AutosavePatterns, filename)
+}
+
+// IsTemp reports whether filename is a temporary file the game writes while saving.
+func (p Profile) IsTemp(filename string) bool {
+	return matchAny(p.TempPatterns, filename)
+}
+
+func matchAny(patterns []string, filename string) bool {
+	lower := strings.ToLower(filename)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, lower); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestParseSaveName(t *testing.T) {
+	p := Default()
+	tests := []struct {
+		filename string
+		want     SaveName
+		ok       bool
+	}{
+		{"PBEM1_turn3_Bob.se1", SaveName{Game: "pbem1", Turn: 3, Player: "bob"}, true},
+		{"pbem1_turn3bob.se1", SaveName{Game: "pbem1", Turn: 3, Player: "bob"}, true},
+		{"pbem1_Bob_turn3.se1", SaveName{Game: "pbem1", Turn: 3, Player: "bob"}, true},
+		{"pbem1_turn3_mr.smith.se1", SaveName{Game: "pbem1", Turn: 3, Player: "mr.smith"}, true},
+		{"pbem1 turn 3 bob.se1", SaveName{}, false},
+		{"bob.se1", SaveName{}, false},
+	}
+	for _, tt := range tests {
+		got, ok := p.ParseSaveName(tt.filename)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseSaveName(%q) = %+v, %v; want %+v, %v", tt.filename, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestMatchesGame(t *testing.T) {
+	p := Default()
+	tests := []struct {
+		filename, game string
+		want           bool
+	}{
+		{"pbem1_turn3_bob.se1", "PBEM1", true},
+		{"PBEM1_Bob_turn3.se1", "pbem1", true},
+		{"pbem10_turn3_bob.se1", "pbem1", false}, // well-formed save of another game
+		{"pbem1_turn3_bob.se1", "pbem10", false},
+		{"my_game_turn3_bob.se1", "my_game", true}, // game names may contain underscores
+		{"pbem1 turn 3 bob.se1", "pbem1", true},    // free-form names fall back to the prefix
+		{"other turn 3 bob.se1", "pbem1", false},
+		{"pbem1_turn3_bob.se1", "", false},
+	}
+	for _, tt := range tests {
+		if got := p.MatchesGame(tt.filename, tt.game); got != tt.want {
+			t.Errorf("MatchesGame(%q, %q) = %v, want %v", tt.filename, tt.game, got, tt.want)
+		}
+	}
+}
+
+func TestMatchesGameFollowsGrammar(t *testing.T) {
+	// A title that puts the game name last
+	p := Default()
+	p.SaveNameFormat = "{player}_turn{turn}_{game}"
+	p.NameGrammar = []*regexp.Regexp{regexp.MustCompile(`^(?P<player>.+)_turn(?P<turn>\d+)_(?P<game>[^_]+)$`)}
+
+	if !p.MatchesGame("bob_turn3_pbem1.se1", "pbem1") {
+		t.Error("MatchesGame rejected a save named by the custom grammar")
+	}
+	if p.MatchesGame("pbem1_turn3_bob.se1", "pbem1") {
+		t.Error("MatchesGame accepted a save the custom grammar names for game 'bob'")
+	}
+}
+
+func TestFormatSaveName(t *testing.T) {
+	if got := Default().FormatSaveName("pbem1", 4, "Alice"); got != "pbem1_turn4_Alice" {
+		t.Errorf("FormatSaveName = %q", got)
+	}
+}
+
+func TestTurnNumber(t *testing.T) {
+	p := Default()
+	tests := map[string]int{
+		"pbem1_turn12_bob.se1":   12,
+		"pbem1_bob_turn7.se1":    7,
+		"pbem1_player_turn3.se1": 3,
+		"pbem1_bob.se1":          0,
+		"pbem1_return5_bob.se1":  0,
+	}
+	for filename, want := range tests {
+		if got := p.TurnNumber(filename); got != want {
+			t.Errorf("TurnNumber(%q) = %d, want %d", filename, got, want)
+		}
+	}
+}
+
+func TestGenericProfile(t *testing.T) {
+	g, ok := Lookup("Generic")
+	if !ok {
+		t.Fatal("generic profile not found")
+	}
+	se := Default()
+	if g.ID != "generic" || g.Extensions != nil || g.ThumbnailURL != "" {
+		t.Errorf("generic profile = %+v", g)
+	}
+	if !g.IsTemp("save.bak") || se.IsTemp("save.bak") {
+		t.Error("only the generic profile should treat .bak files as temporary")
+	}
+	if g.SaveNameFormat != se.SaveNameFormat || len(g.NameGrammar) != len(se.NameGrammar) {
+		t.Error("generic profile should share the Shadow Empire naming scheme")
+	}
+	if !slices.Equal(Names(), []string{"generic", "shadow-empire"}) {
+		t.Errorf("Names() = %v", Names())
+	}
+}
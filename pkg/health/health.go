@@ -0,0 +1,144 @@
+// Package health tracks monitor heartbeats for the health endpoints and the
+// healthcheck subcommand.
+package health
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+)
+
+// DefaultFile is where heartbeats are written unless HEARTBEAT_FILE says otherwise.
+var DefaultFile = filepath.Join(os.TempDir(), "shadow-empire-pbem-bot.heartbeat")
+
+// minStaleAfter keeps slow webhook retries from marking a monitor as stuck
+const minStaleAfter = 2 * time.Minute
+
+// State is the heartbeat record of one game's monitor.
+type State struct {
+	ID             string        `json:"id"`
+	WatchDirectory string        `json:"watch_directory"`
+	Started        time.Time     `json:"started"`
+	LastBeat       time.Time     `json:"last_beat,omitempty"`
+	StaleAfter     time.Duration `json:"stale_after"`
+}
+
+// GameReport is the evaluated health of one game.
+type GameReport struct {
+	ID          string `json:"id,omitempty"`
+	Live        bool   `json:"live"`
+	Ready       bool   `json:"ready"`
+	DirReadable bool   `json:"dir_readable"`
+	LastBeat    string `json:"last_beat,omitempty"`
+	Problem     string `json:"problem,omitempty"`
+}
+
+// Report is the evaluated health of every game. Live means no monitor is stuck;
+// Ready additionally requires every monitor to be running and its directory readable.
+type Report struct {
+	Live  bool         `json:"live"`
+	Ready bool         `json:"ready"`
+	Games []GameReport `json:"games"`
+}
+
+var (
+	mu     sync.Mutex
+	states = make(map[string]*State)
+)
+
+// Register adds a game whose monitor polls every pollInterval. Its heartbeat counts as
+// stale after five missed polls, but never sooner than two minutes.
+func Register(id, watchDirectory string, pollInterval time.Duration) {
+	staleAfter := 5 * pollInterval
+	if staleAfter < minStaleAfter {
+		staleAfter = minStaleAfter
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	states[id] = &State{ID: id, WatchDirectory: watchDirectory, Started: time.Now(), StaleAfter: staleAfter}
+}
+
+// Beat records that the game's monitor completed a poll.
+func Beat(id string) {
+	mu.Lock()
+	defer mu.Unlock()
+	if st, ok := states[id]; ok {
+		st.LastBeat = time.Now()
+	}
+}
+
+// States returns the heartbeat records of every registered game, ordered by ID.
+func States() []State {
+	mu.Lock()
+	defer mu.Unlock()
+	out := make([]State, 0, len(states))
+	for _, st := range states {
+		out = append(out, *st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+// Evaluate checks heartbeats against now and whether each watch directory is readable.
+// A monitor that has not polled yet is live until it is overdue, but not ready.
+func Evaluate(states []State, now time.Time) Report {
+	r := Report{Live: true, Ready: len(states) > 0}
+	for _, st := range states {
+		g := GameReport{ID: st.ID, Live: true, Ready: true, DirReadable: true}
+		last := st.LastBeat
+		if last.IsZero() {
+			last = st.Started
+			g.Ready = false
+			g.Problem = "monitor has not completed a poll yet"
+		} else {
+			g.LastBeat = st.LastBeat.Format(time.RFC3339)
+		}
+		if age := now.Sub(last); age > st.StaleAfter {
+			g.Live, g.Ready = false, false
+			g.Problem = fmt.Sprintf("no heartbeat for %s", age.Truncate(time.Second))
+		}
+		// Remote stores need the game's credentials, so only local directories are checked here
+		if !store.IsRemote(st.WatchDirectory) {
+			if _, err := os.ReadDir(st.WatchDirectory); err != nil {
+				g.Ready, g.DirReadable = false, false
+				g.Problem = fmt.Sprintf("watch directory cannot be read: %v", err)
+			}
+		}
+		r.Live = r.Live && g.Live
+		r.Ready = r.Ready && g.Ready
+		r.Games = append(r.Games, g)
+	}
+	return r
+}
+
+// WriteFile saves the heartbeat records to path, replacing the file atomically.
+func WriteFile(path string) error {
+	data, err := json.Marshal(States())
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// ReadFile loads heartbeat records written by WriteFile.
+func ReadFile(path string) ([]State, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var out []State
+	if err := json.Unmarshal(data, &out); err != nil {
+		return nil, fmt.Errorf("invalid heartbeat file %s: %w", path, err)
+	}
+	return out, nil
+}
@@ -0,0 +1,134 @@
+package health
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeHeartbeat registers one game, optionally beats, and writes the heartbeat file
+func writeHeartbeat(t *testing.T, dir string, beat bool) string {
+	t.Helper()
+	mu.Lock()
+	states = make(map[string]*State)
+	mu.Unlock()
+	t.Cleanup(func() {
+		mu.Lock()
+		states = make(map[string]*State)
+		mu.Unlock()
+	})
+
+	Register("pbem1", dir, 10*time.Second)
+	if beat {
+		Beat("pbem1")
+	}
+	path := filepath.Join(t.TempDir(), "heartbeat")
+	if err := WriteFile(path); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+	return path
+}
+
+func TestEvaluateFreshHeartbeat(t *testing.T) {
+	path := writeHeartbeat(t, t.TempDir(), true)
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if len(got) != 1 || got[0].ID != "pbem1" || got[0].StaleAfter != minStaleAfter {
+		t.Fatalf("ReadFile = %+v, want one pbem1 state stale after %s", got, minStaleAfter)
+	}
+
+	rep := Evaluate(got, time.Now())
+	if !rep.Live || !rep.Ready {
+		t.Errorf("report live=%v ready=%v, want both true", rep.Live, rep.Ready)
+	}
+	g := rep.Games[0]
+	if !g.Live || !g.Ready || !g.DirReadable || g.LastBeat == "" || g.Problem != "" {
+		t.Errorf("game report = %+v, want live, ready and readable with a last beat", g)
+	}
+}
+
+func TestEvaluateStaleHeartbeat(t *testing.T) {
+	path := writeHeartbeat(t, t.TempDir(), true)
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	// Just inside the limit the monitor is still live
+	if rep := Evaluate(got, got[0].LastBeat.Add(minStaleAfter)); !rep.Live || !rep.Ready {
+		t.Errorf("at the limit: live=%v ready=%v, want both true", rep.Live, rep.Ready)
+	}
+
+	rep := Evaluate(got, got[0].LastBeat.Add(minStaleAfter+time.Minute))
+	if rep.Live || rep.Ready {
+		t.Errorf("report live=%v ready=%v, want both false", rep.Live, rep.Ready)
+	}
+	if g := rep.Games[0]; !strings.Contains(g.Problem, "no heartbeat for 3m0s") {
+		t.Errorf("problem = %q, want it to name the heartbeat age", g.Problem)
+	}
+}
+
+func TestEvaluateBeforeFirstPoll(t *testing.T) {
+	path := writeHeartbeat(t, t.TempDir(), false)
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	rep := Evaluate(got, got[0].Started.Add(time.Minute))
+	if !rep.Live || rep.Ready {
+		t.Errorf("starting up: live=%v ready=%v, want live but not ready", rep.Live, rep.Ready)
+	}
+	if g := rep.Games[0]; g.LastBeat != "" || !strings.Contains(g.Problem, "not completed a poll") {
+		t.Errorf("game report = %+v, want no last beat and a startup problem", g)
+	}
+
+	if rep := Evaluate(got, got[0].Started.Add(minStaleAfter+time.Second)); rep.Live {
+		t.Error("a monitor that never polls should stop being live once overdue")
+	}
+}
+
+func TestEvaluateUnreadableDirectory(t *testing.T) {
+	path := writeHeartbeat(t, filepath.Join(t.TempDir(), "missing"), true)
+	got, err := ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+
+	rep := Evaluate(got, time.Now())
+	if !rep.Live || rep.Ready {
+		t.Errorf("report live=%v ready=%v, want live but not ready", rep.Live, rep.Ready)
+	}
+	if g := rep.Games[0]; g.DirReadable || !strings.Contains(g.Problem, "watch directory cannot be read") {
+		t.Errorf("game report = %+v, want an unreadable directory", g)
+	}
+}
+
+func TestMissingHeartbeatFile(t *testing.T) {
+	_, err := ReadFile(filepath.Join(t.TempDir(), "heartbeat"))
+	if !errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("ReadFile error = %v, want not exist", err)
+	}
+
+	// With no games nothing is stuck, but nothing is ready either
+	rep := Evaluate(nil, time.Now())
+	if !rep.Live || rep.Ready || len(rep.Games) != 0 {
+		t.Errorf("empty report = %+v, want live, not ready and no games", rep)
+	}
+}
+
+func TestInvalidHeartbeatFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heartbeat")
+	if err := os.WriteFile(path, []byte(`[{"id":`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadFile(path); err == nil || !strings.Contains(err.Error(), "invalid heartbeat file") {
+		t.Errorf("ReadFile error = %v, want invalid heartbeat file", err)
+	}
+}
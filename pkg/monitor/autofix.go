@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// autoFixBlocker returns why a misnamed save cannot be fixed automatically, or "" if it can.
+// A fix requires a turn number in the filename, exactly one player to match it and a free
+// target name.
+func autoFixBlocker(st store.SaveStore, filename, correctedName string, turn int, userMappings []userparser.UserMapping, fileTracker map[string]*FileTrackingInfo) string {
+	if turn == 0 {
+		return "the filename has no turn number"
+	}
+	matches := 0
+	for _, m := range userMappings {
+		if m.MatchesFilename(filename) {
+			matches++
+		}
+	}
+	if matches != 1 {
+		return fmt.Sprintf("%d players match the filename", matches)
+	}
+	if _, tracked := fileTracker[strings.ToLower(correctedName)]; tracked {
+		return fmt.Sprintf("%s already exists", correctedName)
+	}
+	if _, err := st.Stat(correctedName); err == nil {
+		return fmt.Sprintf("%s already exists", correctedName)
+	}
+	return ""
+}
+
+// autoFixSave copies or renames a misnamed save to correctedName, depending on mode.
+// Copies keep the original modification time.
+func autoFixSave(st store.SaveStore, filename, correctedName, mode string) error {
+	if mode == types.AutoRenameRename {
+		return st.Rename(filename, correctedName)
+	}
+
+	fi, err := st.Stat(filename)
+	if err != nil {
+		return err
+	}
+	in, err := st.Open(filename)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	return st.Write(correctedName, in, fi.Size, fi.ModTime)
+}
@@ -0,0 +1,70 @@
+package monitor
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+func TestAutoFixBlocker(t *testing.T) {
+	// The parser rejects names containing each other, so build the ambiguous roster directly
+	users := []userparser.UserMapping{
+		{Order: 1, Username: "Alice", DiscordID: "123456789012345678"},
+		{Order: 2, Username: "Bob", DiscordID: "223456789012345678"},
+		{Order: 3, Username: "Bobby", DiscordID: "323456789012345678"},
+	}
+	st := store.NewMemory()
+	st.Put("pbem1_turn2_Alice.se1", []byte("taken"), time.Time{})
+	tracker := map[string]*FileTrackingInfo{"pbem1_turn4_alice.se1": {Processed: true}}
+
+	tests := []struct {
+		filename, corrected string
+		turn                int
+		want                string
+	}{
+		{"game_turn3_alice.se1", "pbem1_turn3_Alice.se1", 3, ""},
+		{"game_alice.se1", "pbem1_turn3_Alice.se1", 0, "the filename has no turn number"},
+		{"game_turn3_bobby.se1", "pbem1_turn3_Bobby.se1", 3, "2 players match the filename"},
+		{"game_turn3_carol.se1", "pbem1_turn3_Carol.se1", 3, "0 players match the filename"},
+		{"game_turn2_alice.se1", "pbem1_turn2_Alice.se1", 2, "pbem1_turn2_Alice.se1 already exists"},
+		{"game_turn4_alice.se1", "pbem1_turn4_Alice.se1", 4, "pbem1_turn4_Alice.se1 already exists"},
+	}
+	for _, tt := range tests {
+		if got := autoFixBlocker(st, tt.filename, tt.corrected, tt.turn, users, tracker); got != tt.want {
+			t.Errorf("autoFixBlocker(%q) = %q, want %q", tt.filename, got, tt.want)
+		}
+	}
+}
+
+func TestAutoFixSave(t *testing.T) {
+	modTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	for _, mode := range []string{types.AutoRenameCopy, types.AutoRenameRename} {
+		st := store.NewMemory()
+		st.Put("game_turn3_alice.se1", []byte("save data"), modTime)
+
+		if err := autoFixSave(st, "game_turn3_alice.se1", "pbem1_turn3_Alice.se1", mode); err != nil {
+			t.Fatalf("%s: %v", mode, err)
+		}
+		fi, err := st.Stat("pbem1_turn3_Alice.se1")
+		if err != nil {
+			t.Fatalf("%s: corrected save missing: %v", mode, err)
+		}
+		if !fi.ModTime.Equal(modTime) {
+			t.Errorf("%s: modification time = %s, want %s", mode, fi.ModTime, modTime)
+		}
+		r, _ := st.Open("pbem1_turn3_Alice.se1")
+		data, _ := io.ReadAll(r)
+		r.Close()
+		if string(data) != "save data" {
+			t.Errorf("%s: corrected save holds %q", mode, data)
+		}
+		_, err = st.Stat("game_turn3_alice.se1")
+		if kept := err == nil; kept != (mode == types.AutoRenameCopy) {
+			t.Errorf("%s: original kept = %v", mode, kept)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncfiles"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// Kinds of file verdicts
+const (
+	VerdictSave     = "save"     // a turn save handed to the named player
+	VerdictMisnamed = "misnamed" // reported to the player or auto-fixed
+	VerdictUnknown  = "unknown"  // a save that matches no player
+	VerdictResign   = "resign"   // a resignation file
+	VerdictIgnored  = "ignored"  // matches IGNORE_PATTERNS
+	VerdictSkipped  = "skipped"  // not a save of this game
+	VerdictConflict = "conflict" // a sync tool's conflict copy of a save
+)
+
+// Verdict describes how the monitor treats a file in the watch directory.
+type Verdict struct {
+	Kind      string
+	Reason    string
+	Turn      int
+	Player    string // player whose turn the save starts, or who resigned
+	Corrected string // expected name of a misnamed save
+}
+
+// Classify reports how the monitor would treat filename without acting on it,
+// following the same checks as the polling loop.
+func Classify(filename string, cfg types.Config, users []userparser.UserMapping) Verdict {
+	if uname, ok := matchResignUsername(filename, cfg.GameName, users); ok {
+		return Verdict{Kind: VerdictResign, Player: uname, Reason: "resignation of " + uname}
+	}
+
+	lower := strings.ToLower(filename)
+	if tool, ok := syncfiles.Temp(filename); ok {
+		return Verdict{Kind: VerdictSkipped, Reason: tool + " transfer or lock file"}
+	}
+	if c, ok := syncfiles.ParseConflict(filename); ok {
+		original := strings.ToLower(c.Original)
+		if isSaveCandidate(original, cfg) && !belongsToOtherGame(original, cfg) {
+			return Verdict{Kind: VerdictConflict, Reason: fmt.Sprintf("%s conflict copy of %s", c.Tool, c.Original)}
+		}
+		return Verdict{Kind: VerdictSkipped, Reason: c.Tool + " conflict copy"}
+	}
+
+	profile := cfg.Profile()
+	switch {
+	case !hasAllowedExtension(lower, cfg.AllowedExtensions):
+		return Verdict{Kind: VerdictSkipped, Reason: fmt.Sprintf("extension not in %s", strings.Join(cfg.AllowedExtensions, ", "))}
+	case profile.IsTemp(lower):
+		return Verdict{Kind: VerdictSkipped, Reason: "temporary file"}
+	case profile.IsAutosave(lower):
+		return Verdict{Kind: VerdictSkipped, Reason: "autosave"}
+	case !isSaveCandidate(lower, cfg):
+		return Verdict{Kind: VerdictSkipped, Reason: "roster file"}
+	case belongsToOtherGame(lower, cfg):
+		return Verdict{Kind: VerdictSkipped, Reason: "save of another game in this directory"}
+	case shouldIgnoreFile(lower, cfg.IgnorePatterns):
+		return Verdict{Kind: VerdictIgnored, Reason: "matches IGNORE_PATTERNS"}
+	}
+
+	v := Verdict{Turn: profile.TurnNumber(lower)}
+	idx := findUserIndex(lower, users)
+	if idx != -1 {
+		v.Player = users[idx].Username
+	}
+	if !profile.MatchesGame(lower, cfg.GameName) {
+		v.Kind = VerdictMisnamed
+		v.Reason = fmt.Sprintf("is not named for game '%s'", cfg.GameName)
+		if idx != -1 && v.Turn > 0 {
+			v.Corrected = profile.FormatSaveName(cfg.GameName, v.Turn, v.Player) + filepath.Ext(filename)
+		}
+		return v
+	}
+	if idx == -1 {
+		v.Kind = VerdictUnknown
+		v.Reason = "no player name or alias found"
+		return v
+	}
+	v.Kind = VerdictSave
+	return v
+}
@@ -4,16 +4,20 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
-	"regexp"
 	"sort"
-	"strconv"
 	"strings"
 	"time"
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/health"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/supervisor"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncfiles"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncthing"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/watcher"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
 )
 
@@ -22,6 +26,15 @@ type FileTrackingInfo struct {
 	FirstSeen int64
 	Processed bool
 	LastSize  int64
+	// ModTime is the modification time in nanoseconds at the last observation
+	ModTime int64
+	// ETag is the store's change token at the last observation, for stores that have one
+	ETag string
+	// Synced is set when Syncthing reports the file fully synced, ending the debounce period early
+	Synced bool
+	// Hash is the hex SHA-256 of the contents once observed after the debounce period,
+	// kept after processing for deduplication and integrity checks
+	Hash string
 }
 
 // TurnInfo stores information about the current player's turn
@@ -32,6 +45,7 @@ type TurnInfo struct {
 	NextUsername   string
 	TurnNumber     int
 	LastRemindedAt time.Time
+	RemindersSent  int
 }
 
 // normalize lowercases and trims a string
@@ -48,35 +62,61 @@ func normalize(s string) string { return strings.ToLower(strings.TrimSpace(s)) }
 //   - <username>-resign
 //   - <game>_resign_<username>
 //   - <game>_resign-<username>
+//
+// Usernames and aliases are compared after Unicode folding, so "resign_joerg"
+// matches a player named "Jörg".
 func matchResignUsername(filename, gameName string, userMappings []userparser.UserMapping) (string, bool) {
 	// remove extension if any (we only compare base name semantics)
 	base := filename
 	if ext := filepath.Ext(base); ext != "" {
 		base = strings.TrimSuffix(base, ext)
 	}
-	lf := normalize(base)
-	lg := normalize(gameName)
+	variants := userparser.FoldVariants(base)
+	lg := userparser.FoldName(gameName)
 	for _, u := range userMappings {
-		un := normalize(u.Username)
-		candidates := []string{
-			un,
-			un + ".resign", // in case ext remained in the name itself
-			"resign_" + un,
-			"resign-" + un,
-			un + "_resign",
-			un + "-resign",
-			lg + "_resign_" + un,
-			lg + "_resign-" + un,
-		}
-		for _, c := range candidates {
-			if lf == c {
-				return u.Username, true
+		for _, un := range u.MatchKeys() {
+			candidates := []string{
+				un,
+				un + ".resign", // in case ext remained in the name itself
+				"resign_" + un,
+				"resign-" + un,
+				un + "_resign",
+				un + "-resign",
+				lg + "_resign_" + un,
+				lg + "_resign-" + un,
+			}
+			for _, c := range candidates {
+				for _, lf := range variants {
+					if lf == c {
+						return u.Username, true
+					}
+				}
 			}
 		}
 	}
 	return "", false
 }
 
+// findUserIndex returns the index of the first user whose name or alias appears in filename, or -1
+func findUserIndex(filename string, userMappings []userparser.UserMapping) int {
+	for i, mapping := range userMappings {
+		if mapping.MatchesFilename(filename) {
+			return i
+		}
+	}
+	return -1
+}
+
+// findUserByName returns the mapping whose username equals name (case-insensitive)
+func findUserByName(name string, userMappings []userparser.UserMapping) (userparser.UserMapping, bool) {
+	for _, m := range userMappings {
+		if normalize(m.Username) == normalize(name) {
+			return m, true
+		}
+	}
+	return userparser.UserMapping{}, false
+}
+
 // filterUserMappings removes any users present in resigned map (case-insensitive keys)
 func filterUserMappings(mappings []userparser.UserMapping, resigned map[string]bool) []userparser.UserMapping {
 	if len(resigned) == 0 {
@@ -91,20 +131,16 @@ func filterUserMappings(mappings []userparser.UserMapping, resigned map[string]b
 	return out
 }
 
-// scanResignations scans dirPath for files indicating player resignation.
+// scanResignations scans the store for files indicating player resignation.
 // It returns a map of normalized usernames who have resigned.
-func scanResignations(dirPath, gameName string, userMappings []userparser.UserMapping) map[string]bool {
+func scanResignations(st store.SaveStore, gameName string, userMappings []userparser.UserMapping) map[string]bool {
 	resigned := make(map[string]bool)
-	entries, err := os.ReadDir(dirPath)
+	names, err := st.List()
 	if err != nil {
 		log.Printf("❌ Error reading directory for resignations: %v\n", err)
 		return resigned
 	}
-	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := e.Name()
+	for _, name := range names {
 		if uname, ok := matchResignUsername(name, gameName, userMappings); ok {
 			resigned[normalize(uname)] = true
 		}
@@ -151,18 +187,84 @@ func hasAllowedExtension(filename string, exts []string) bool {
 	return false
 }
 
-// MonitorDirectory monitors a directory for new save files and notifies the next player
-func MonitorDirectory(ctx context.Context, cfg types.Config) {
+// isSaveCandidate checks if a file could be a turn save: it has an allowed extension and is
+// neither a temp or autosave file of the game profile nor a roster file
+func isSaveCandidate(filename string, cfg types.Config) bool {
+	if !hasAllowedExtension(filename, cfg.AllowedExtensions) {
+		return false
+	}
+	profile := cfg.Profile()
+	if profile.IsTemp(filename) || profile.IsAutosave(filename) {
+		return false
+	}
+	for _, name := range userparser.RosterFileNames {
+		if strings.EqualFold(filename, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// belongsToOtherGame reports whether filename is a save of another game sharing the watch directory.
+// For free-form names matched by prefix, the longest game name wins, so "pbem10 ..." is not
+// mistaken for a save of game "pbem1".
+func belongsToOtherGame(filename string, cfg types.Config) bool {
+	profile := cfg.Profile()
+	own := profile.MatchesGame(filename, cfg.GameName)
+	for _, other := range cfg.ForeignGameNames {
+		if profile.MatchesGame(filename, other) && (!own || len(other) > len(cfg.GameName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// MonitorDirectory monitors a directory for new save files and notifies the next player.
+// Configuration reloads published through live are picked up on the next poll.
+// It returns nil when ctx is canceled, or an error if the game cannot be started.
+func MonitorDirectory(ctx context.Context, live *types.LiveConfig) error {
+	cfg := live.Get()
+	st, err := store.Open(cfg.WatchDirectory, cfg.StoreOptions())
+	if err != nil {
+		return supervisor.Permanent(err)
+	}
+	return MonitorStore(ctx, live, st)
+}
+
+// MonitorStore monitors the saves in st like MonitorDirectory. File notifications are only
+// used when st is a local directory; other stores are polled.
+func MonitorStore(ctx context.Context, live *types.LiveConfig, st store.SaveStore) error {
+	// Stop background helpers such as the Syncthing follower whenever the monitor returns
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	seenVersion := live.Version()
+	cfg := live.Get()
 	dirPath := cfg.WatchDirectory
 
-	// Get username to Discord ID mappings from config
-	userMappings, err := userparser.ParseUsersFromString(cfg.UserMappingsRaw)
+	// Get username to Discord ID mappings from the roster file, config file players or USER_MAPPINGS.
+	// A bad configuration will not fix itself, so it is not worth restarting for.
+	rosterPath := cfg.RosterPath()
+	userMappings, skipped, err := cfg.LoadPlayers()
+	if skipped != nil {
+		log.Printf("⚠️ Ignoring %v. Falling back to the configured players until it is fixed.\n", skipped)
+	}
 	if err != nil {
-		log.Fatalf("❌ Failed to parse USER_MAPPINGS: %v. Please check the format (e.g., '1 User1 ID1,2 User2 ID2').", err)
+		return supervisor.Permanent(err)
+	}
+	if err := resolveDisplayNames(ctx, cfg, userMappings); err != nil {
+		return supervisor.Permanent(fmt.Errorf("failed to verify players with Discord: %w", err))
+	}
+
+	// Watch the roster file so players can edit their own entries without a restart
+	var roster *rosterWatcher
+	if rosterPath != "" {
+		roster = newRosterWatcher(rosterPath)
+		log.Printf("📇 Watching roster file %s for changes\n", rosterPath)
 	}
 
 	// Apply resignations from files at startup
-	resigned := scanResignations(dirPath, cfg.GameName, userMappings)
+	resigned := scanResignations(st, cfg.GameName, userMappings)
 	activeMappings := filterUserMappings(userMappings, resigned)
 	if len(resigned) > 0 {
 		// Log resigned users (do not send notifications on startup)
@@ -180,9 +282,13 @@ func MonitorDirectory(ctx context.Context, cfg types.Config) {
 	}
 
 	// Log the parsed user mappings (active only)
-	log.Printf("👥 Loaded %d active user mappings (of %d total):\n", len(activeMappings), len(userMappings))
+	log.Printf("👥 %sLoaded %d active user mappings (of %d total):\n", cfg.Label(), len(activeMappings), len(userMappings))
 	for _, mapping := range activeMappings {
-		log.Printf("  - Order: %d, User: %s, ID: %s\n", mapping.Order, mapping.Username, maskID(mapping.DiscordID))
+		if len(mapping.Aliases) > 0 {
+			log.Printf("  - Order: %d, User: %s (aliases: %s), ID: %s\n", mapping.Order, mapping.Label(), strings.Join(mapping.Aliases, ", "), maskID(mapping.DiscordID))
+		} else {
+			log.Printf("  - Order: %d, User: %s, ID: %s\n", mapping.Order, mapping.Label(), maskID(mapping.DiscordID))
+		}
 	}
 
 	// File tracking map with timestamps to implement debouncing
@@ -194,34 +300,26 @@ func MonitorDirectory(ctx context.Context, cfg types.Config) {
 	// Current player turn tracking
 	var currentTurnInfo *TurnInfo = nil
 
-	// Reminder interval from cfg
-	reminderIntervalMinutes := cfg.ReminderIntervalMinutes
-
-	// Reminder interval printed in main; skip repeating here
-
-	// File debounce from cfg
-	fileDebounceMs := cfg.FileDebounceMs
-	fmt.Printf("⏱️ File debounce time set to %d seconds\n", fileDebounceMs/1000)
+	// File debounce from cfg; the reminder interval is printed in main
+	fileDebounce := cfg.FileDebounce
+	fmt.Printf("⏱️ File debounce time set to %s\n", duration.Format(fileDebounce))
 
 	// Initialize tracker with existing files as already processed
-	files, err := os.ReadDir(dirPath)
+	files, err := st.List()
 	if err != nil {
-		fmt.Printf("❌ Error reading directory: %v\n", err)
-		return
+		return fmt.Errorf("error reading directory: %w", err)
 	}
 
-	for _, file := range files {
-		if !file.IsDir() {
-			lowerFilename := strings.ToLower(file.Name())
-			fileTracker[lowerFilename] = &FileTrackingInfo{
-				FirstSeen: time.Now().UnixMilli(),
-				Processed: true,
-			}
+	for _, name := range files {
+		lowerFilename := strings.ToLower(name)
+		fileTracker[lowerFilename] = &FileTrackingInfo{
+			FirstSeen: time.Now().UnixMilli(),
+			Processed: true,
 		}
 	}
 
 	// Bootstrap current turn info from the most recent valid save so reminders resume after restart
-	if info, ct := bootstrapCurrentTurnFromExistingFiles(dirPath, files, activeMappings, cfg); info != nil {
+	if info, ct := bootstrapCurrentTurnFromExistingFiles(st, files, activeMappings, cfg); info != nil {
 		currentTurnInfo = info
 		if ct > 0 {
 			currentTurn = ct
@@ -232,232 +330,445 @@ func MonitorDirectory(ctx context.Context, cfg types.Config) {
 	log.Printf("📋 Initialized with %d existing files\n", len(fileTracker))
 
 	// Set up polling interval
-	pollInterval := time.Duration(cfg.PollIntervalSec) * time.Second
+	pollInterval := cfg.PollInterval
 
-	log.Printf("👁️ Started monitoring directory: %s (polling every %v)\n", dirPath, pollInterval)
+	log.Printf("👁️ %sStarted monitoring directory: %s for game %s (polling every %v)\n", cfg.Label(), dirPath, cfg.GameName, pollInterval)
+	publishStatus(cfg, currentTurn, currentTurnInfo, activeMappings)
 
 	ticker := time.NewTicker(pollInterval)
 	defer ticker.Stop()
 
-	reminderIntervalDuration := time.Duration(reminderIntervalMinutes) * time.Minute
 	// Track a snapshot of resignations to log only when it changes
 	lastResignSnapshot := ""
 
+	// Watch for file events; the ticker keeps housekeeping and the safety-net rescans going
+	w := watcher.Polling("the save store has no file notifications")
+	if d, ok := st.(*store.Dir); ok {
+		w = watcher.New(d.Path, cfg.WatchMode)
+	}
+	defer w.Close()
+	if w.Mode == watcher.ModeNotify {
+		log.Printf("⚡ %sUsing file notifications for %s (full rescan every %s)\n", cfg.Label(), dirPath, duration.Format(cfg.RescanInterval))
+	} else {
+		log.Printf("🐢 %sPolling %s every %s: %s\n", cfg.Label(), dirPath, duration.Format(pollInterval), w.Reason)
+	}
+	lastScan := time.Now()
+	changed := false
+
+	// Follow Syncthing to process saves as soon as they are fully synced
+	var syncUpdates chan syncthing.Update
+	if cfg.SyncthingAPIKey != "" {
+		syncUpdates = make(chan syncthing.Update, 16)
+		follower := &syncthing.Follower{
+			Client:   syncthing.NewClient(cfg.SyncthingURL, cfg.SyncthingAPIKey),
+			FolderID: cfg.SyncthingFolder,
+			Dir:      dirPath,
+			Label:    cfg.Label(),
+		}
+		go follower.Run(ctx, syncUpdates)
+	}
+
+	// setPlayers switches to an updated player list and announces what changed
+	setPlayers := func(updated []userparser.UserMapping, source string) {
+		changes := userparser.DiffMappings(userMappings, updated)
+		if len(changes) == 0 {
+			return
+		}
+		log.Printf("📇 Roster updated from %s:\n", source)
+		for _, c := range changes {
+			log.Printf("  %s\n", c)
+		}
+		previous := userMappings
+		userMappings = updated
+		if err := webhook.SendRosterUpdateWebHook(changes, cfg); err != nil {
+			log.Printf("❌ Failed to send roster update notification: %v\n", err)
+		}
+		if currentTurnInfo != nil {
+			if m, ok := findUserByName(currentTurnInfo.Username, userMappings); ok {
+				currentTurnInfo.DiscordID = m.DiscordID
+			} else {
+				// Nobody would ever save for a removed player, so the next one takes over
+				currentTurn, currentTurnInfo = passTurnFromRemoved(currentTurnInfo, previous, filterUserMappings(userMappings, resigned), currentTurn, cfg)
+			}
+		}
+	}
+
+	// reloadPlayers loads the players again, watching the roster file the configuration now points to
+	reloadPlayers := func(source string) {
+		if path := cfg.RosterPath(); path != rosterPath {
+			rosterPath, roster = path, nil
+			if rosterPath != "" {
+				roster = newRosterWatcher(rosterPath)
+				log.Printf("📇 Watching roster file %s for changes\n", rosterPath)
+				source = rosterPath
+			}
+		}
+		updated, skipped, err := cfg.LoadPlayers()
+		if skipped != nil {
+			log.Printf("⚠️ Ignoring %v. Falling back to the configured players until it is fixed.\n", skipped)
+		}
+		if err == nil {
+			err = resolveDisplayNames(ctx, cfg, updated)
+		}
+		if err != nil {
+			log.Printf("❌ %sKeeping the current players, the reloaded player list was rejected: %v\n", cfg.Label(), err)
+			if err := webhook.SendAdminAlertWebHook("Player list rejected", err.Error(), nil, cfg); err != nil {
+				log.Printf("❌ Failed to send admin alert: %v\n", err)
+			}
+			return
+		}
+		setPlayers(updated, source)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Println("🛑 Shutting down monitor...")
-			return
+			fmt.Printf("🛑 %sShutting down monitor...\n", cfg.Label())
+			return nil
+		case <-w.Changes():
+			changed = true
+		case u := <-syncUpdates:
+			if handleSyncthingUpdate(u, st, fileTracker, userMappings, cfg) {
+				changed = true
+			}
 		case <-ticker.C:
-			// Refresh resignations each tick
-			prevResigned := resigned
-			resigned = scanResignations(dirPath, cfg.GameName, userMappings)
-			activeMappings = filterUserMappings(userMappings, resigned)
-			if len(resigned) > 0 {
-				names := make([]string, 0, len(resigned))
-				for k := range resigned {
-					names = append(names, k)
+		}
+
+		// Record that this loop is alive for the health checks
+		health.Beat(cfg.ID)
+
+		// Apply a reloaded configuration as a whole
+		if v := live.Version(); v != seenVersion {
+			seenVersion = v
+			prev := cfg
+			cfg = live.Get()
+			ignorePatterns = cfg.IgnorePatterns
+			fileDebounce = cfg.FileDebounce
+			if cfg.PollInterval != pollInterval {
+				pollInterval = cfg.PollInterval
+				ticker.Reset(pollInterval)
+			}
+			log.Printf("🔄 %sApplied reloaded configuration\n", cfg.Label())
+
+			// Players set in the configuration change with it; a new roster file is watched instead
+			if playersChanged(prev, cfg) {
+				reloadPlayers("the configuration")
+			}
+		}
+
+		// Pick up a players.yaml created in the watch directory since startup; a removed one
+		// keeps the last good roster like any other unreadable roster file
+		if path := cfg.RosterPath(); path != "" && path != rosterPath {
+			reloadPlayers(path)
+		}
+
+		// Apply roster edits, keeping the last good roster if the new one is invalid
+		if roster != nil && roster.changed() {
+			updated, err := roster.reload()
+			if err == nil {
+				err = resolveDisplayNames(ctx, cfg, updated)
+			}
+			if err != nil {
+				log.Printf("❌ Rejected roster update from %s: %v\n", roster.path, err)
+				if err := webhook.SendRosterErrorWebHook(roster.path, err, cfg); err != nil {
+					log.Printf("❌ Failed to send roster error notification: %v\n", err)
 				}
-				// stable order for snapshot
-				sort.Strings(names)
-				snapshot := strings.Join(names, ",")
-				if snapshot != lastResignSnapshot {
-					log.Printf("🚪 Resignations detected: %s\n", strings.Join(names, ", "))
-					lastResignSnapshot = snapshot
-					// Notify for any newly resigned players
-					for _, m := range userMappings {
-						if resigned[normalize(m.Username)] && !prevResigned[normalize(m.Username)] {
-							if err := webhook.SendResignationWebHook(m.Username, m.DiscordID, cfg); err != nil {
-								log.Printf("❌ Failed to send resignation notification for %s: %v\n", m.Username, err)
-							}
+			} else {
+				setPlayers(updated, roster.path)
+			}
+		}
+
+		// Scan on every tick when polling; with file notifications only after a change,
+		// while new saves are settling, or when the safety-net rescan is due
+		scan := w.Mode == watcher.ModePoll || changed || hasPending(fileTracker) || time.Since(lastScan) >= cfg.RescanInterval
+		if scan {
+			changed = false
+			lastScan = time.Now()
+		}
+
+		// Refresh resignations on each scan
+		prevResigned := resigned
+		if scan {
+			resigned = scanResignations(st, cfg.GameName, userMappings)
+		}
+		activeMappings = filterUserMappings(userMappings, resigned)
+		if len(resigned) > 0 {
+			names := make([]string, 0, len(resigned))
+			for k := range resigned {
+				names = append(names, k)
+			}
+			// stable order for snapshot
+			sort.Strings(names)
+			snapshot := strings.Join(names, ",")
+			if snapshot != lastResignSnapshot {
+				log.Printf("🚪 Resignations detected: %s\n", strings.Join(names, ", "))
+				lastResignSnapshot = snapshot
+				// Notify for any newly resigned players
+				for _, m := range userMappings {
+					if resigned[normalize(m.Username)] && !prevResigned[normalize(m.Username)] {
+						if err := webhook.SendResignationWebHook(m.Label(), m.DiscordID, cfg); err != nil {
+							log.Printf("❌ Failed to send resignation notification for %s: %v\n", m.Username, err)
 						}
 					}
 				}
 			}
+		}
 
-			// Cancel reminders if current player resigned
-			if currentTurnInfo != nil {
-				isActive := false
-				for _, m := range activeMappings {
-					if normalize(m.Username) == normalize(currentTurnInfo.Username) {
-						isActive = true
-						break
-					}
-				}
-				if !isActive {
-					log.Printf("🚪 Current player %s resigned; canceling reminders until next save\n", currentTurnInfo.Username)
-					currentTurnInfo = nil
+		// Cancel reminders if current player resigned
+		if currentTurnInfo != nil {
+			isActive := false
+			for _, m := range activeMappings {
+				if normalize(m.Username) == normalize(currentTurnInfo.Username) {
+					isActive = true
+					break
 				}
 			}
-
-			if len(activeMappings) < 2 {
-				// Not enough players to maintain a turn order
-				log.Printf("⚠️ Only %d active player(s) after resignations; skipping processing this tick\n", len(activeMappings))
-				continue
+			if !isActive {
+				log.Printf("🚪 Current player %s resigned; canceling reminders until next save\n", currentTurnInfo.Username)
+				currentTurnInfo = nil
 			}
+		}
 
-			// Ensure the reminder target (NextUsername) skips any resigned players
-			if currentTurnInfo != nil {
-				// Find current player's index within active mappings
-				idx := -1
-				for i, m := range activeMappings {
-					if normalize(m.Username) == normalize(currentTurnInfo.Username) {
-						idx = i
-						break
-					}
+		if len(activeMappings) < 2 {
+			// Not enough players to maintain a turn order
+			log.Printf("⚠️ Only %d active player(s) after resignations; skipping processing this tick\n", len(activeMappings))
+			publishStatus(cfg, currentTurn, currentTurnInfo, activeMappings)
+			continue
+		}
+
+		// Ensure the reminder target (NextUsername) skips any resigned players
+		if currentTurnInfo != nil {
+			// Find current player's index within active mappings
+			idx := -1
+			for i, m := range activeMappings {
+				if normalize(m.Username) == normalize(currentTurnInfo.Username) {
+					idx = i
+					break
 				}
-				if idx != -1 {
-					nextIdx := (idx + 1) % len(activeMappings)
-					newNext := activeMappings[nextIdx].Username
-					if newNext != currentTurnInfo.NextUsername {
-						log.Printf("🔧 Next player changed due to resignation(s): %s -> %s\n", currentTurnInfo.NextUsername, newNext)
-						currentTurnInfo.NextUsername = newNext
-					}
+			}
+			if idx != -1 {
+				nextIdx := (idx + 1) % len(activeMappings)
+				newNext := activeMappings[nextIdx].Username
+				if newNext != currentTurnInfo.NextUsername {
+					log.Printf("🔧 Next player changed due to resignation(s): %s -> %s\n", currentTurnInfo.NextUsername, newNext)
+					currentTurnInfo.NextUsername = newNext
 				}
 			}
+		}
 
-			// Process directory for new files using active mappings
-			currentTurn, currentTurnInfo = processDirectory(dirPath, fileTracker, activeMappings, fileDebounceMs, ignorePatterns, cfg, currentTurn, currentTurnInfo)
-
-			// Check if we should send a reminder
-			if currentTurnInfo != nil {
-				timeSinceTurnStart := time.Since(currentTurnInfo.StartedAt)
-				timeSinceLastReminder := time.Since(currentTurnInfo.LastRemindedAt)
-
-				// If this is the first reminder or enough time has passed since the last reminder
-				if (currentTurnInfo.LastRemindedAt.IsZero() && timeSinceTurnStart >= reminderIntervalDuration) ||
-					(!currentTurnInfo.LastRemindedAt.IsZero() && timeSinceLastReminder >= reminderIntervalDuration) {
-
-					// Send reminder
-					minutesElapsed := int(timeSinceTurnStart.Minutes())
-					hours := minutesElapsed / 60
-					minutes := minutesElapsed % 60
-
-					if hours > 0 && minutes > 0 {
-						log.Printf("⏰ Sending turn reminder to %s (%s) - %d hours and %d minutes elapsed since turn start\n",
-							currentTurnInfo.Username, maskID(currentTurnInfo.DiscordID), hours, minutes)
-					} else if hours > 0 {
-						log.Printf("⏰ Sending turn reminder to %s (%s) - %d hours elapsed since turn start\n",
-							currentTurnInfo.Username, maskID(currentTurnInfo.DiscordID), hours)
-					} else {
-						log.Printf("⏰ Sending turn reminder to %s (%s) - %d minutes elapsed since turn start\n",
-							currentTurnInfo.Username, maskID(currentTurnInfo.DiscordID), minutes)
-					}
+		// Process directory for new files using active mappings
+		if scan {
+			currentTurn, currentTurnInfo = processDirectory(st, fileTracker, activeMappings, fileDebounce, ignorePatterns, cfg, currentTurn, currentTurnInfo)
+		}
 
-					err := webhook.SendReminderWebHook(
-						currentTurnInfo.Username,
-						currentTurnInfo.DiscordID,
-						currentTurnInfo.NextUsername,
-						currentTurnInfo.TurnNumber,
-						minutesElapsed,
-						cfg,
-					)
-
-					if err == nil {
-						// Update the last reminded time
-						currentTurnInfo.LastRemindedAt = time.Now()
-					} else {
-						fmt.Printf("❌ Failed to send reminder: %v\n", err)
-					}
+		publishStatus(cfg, currentTurn, currentTurnInfo, activeMappings)
+
+		// Check if we should send a reminder
+		if currentTurnInfo != nil {
+			timeSinceTurnStart := time.Since(currentTurnInfo.StartedAt)
+			timeSinceLastReminder := time.Since(currentTurnInfo.LastRemindedAt)
+
+			// Apply the current player's reminder policy from the roster, if any
+			player, _ := findUserByName(currentTurnInfo.Username, activeMappings)
+			interval := cfg.ReminderInterval
+			if custom, _ := player.Reminders.IntervalDuration(); custom > 0 {
+				interval = custom
+			}
+			allowed := !player.Reminders.Disabled &&
+				(player.Reminders.Max == 0 || currentTurnInfo.RemindersSent < player.Reminders.Max) &&
+				!player.InQuietHours(time.Now())
+
+			// If this is the first reminder or enough time has passed since the last reminder
+			if allowed && ((currentTurnInfo.LastRemindedAt.IsZero() && timeSinceTurnStart >= interval) ||
+				(!currentTurnInfo.LastRemindedAt.IsZero() && timeSinceLastReminder >= interval)) {
+
+				// Send reminder
+				elapsed := timeSinceTurnStart.Truncate(time.Minute)
+				log.Printf("⏰ Sending turn reminder to %s (%s) - %s elapsed since turn start\n",
+					currentTurnInfo.Username, maskID(currentTurnInfo.DiscordID), duration.Format(elapsed))
+
+				err := webhook.SendReminderWebHook(
+					currentTurnInfo.Username,
+					currentTurnInfo.DiscordID,
+					currentTurnInfo.NextUsername,
+					currentTurnInfo.TurnNumber,
+					elapsed,
+					cfg,
+				)
+
+				if err == nil {
+					// Update the last reminded time
+					currentTurnInfo.LastRemindedAt = time.Now()
+					currentTurnInfo.RemindersSent++
+				} else {
+					fmt.Printf("❌ Failed to send reminder: %v\n", err)
 				}
 			}
 		}
 	}
 }
 
-// extractTurnNumber attempts to extract turn number from a filename
-func extractTurnNumber(filename string) int {
-	// Support: PBEM1_turn1_Player, PBEM1_Player_turn1, and end-of-string variations
-	lower := strings.ToLower(filename)
-	patterns := []*regexp.Regexp{
-		regexp.MustCompile(`(?:^|_)turn(\d+)(?:_|$)`),         // _turn1_ or _turn1 end
-		regexp.MustCompile(`(?:^|_)player_?turn(\d+)(?:_|$)`), // _player_turn1
-	}
-	for _, rx := range patterns {
-		if m := rx.FindStringSubmatch(lower); len(m) > 1 {
-			if n, err := strconv.Atoi(m[1]); err == nil {
-				return n
-			}
+// reportSyncConflict alerts the player who made a save that a sync tool left competing versions of it
+func reportSyncConflict(st store.SaveStore, c syncfiles.Conflict, files []string, userMappings []userparser.UserMapping, cfg types.Config) {
+	// List the original followed by every conflict copy of it
+	var versions []string
+	describe := func(name string) {
+		fi, err := st.Stat(name)
+		if err != nil {
+			return
+		}
+		versions = append(versions, fmt.Sprintf("`%s` (%d bytes, modified %s)", name, fi.Size, fi.ModTime.Format("2006-01-02 15:04:05")))
+	}
+	for _, name := range files {
+		if strings.EqualFold(name, c.Original) {
+			describe(name)
+		}
+	}
+	for _, name := range files {
+		if other, ok := syncfiles.ParseConflict(name); ok && strings.EqualFold(other.Original, c.Original) {
+			describe(name)
 		}
 	}
-	return 0
+
+	// The player who saved comes before the one named in the file
+	var username, discordID string
+	if idx := findUserIndex(strings.ToLower(c.Original), userMappings); idx != -1 {
+		prev := userMappings[(idx-1+len(userMappings))%len(userMappings)]
+		username, discordID = prev.Username, prev.DiscordID
+	}
+	if err := webhook.SendSyncConflictWebHook(username, discordID, c.Tool, c.Original, versions, cfg); err != nil {
+		fmt.Printf("❌ Failed to send sync conflict notification: %v\n", err)
+	}
+}
+
+// hasPending reports whether any tracked file is still waiting out its debounce period
+func hasPending(fileTracker map[string]*FileTrackingInfo) bool {
+	for _, info := range fileTracker {
+		if !info.Processed {
+			return true
+		}
+	}
+	return false
 }
 
 // processDirectory handles a single directory scan iteration
 // Returns the current turn number and turn info (possibly updated)
-func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
+func processDirectory(st store.SaveStore, fileTracker map[string]*FileTrackingInfo,
 	userMappings []userparser.UserMapping,
-	fileDebounceMs int, ignorePatterns []string, cfg types.Config, currentTurn int, currentTurnInfo *TurnInfo) (int, *TurnInfo) {
+	fileDebounce time.Duration, ignorePatterns []string, cfg types.Config, currentTurn int, currentTurnInfo *TurnInfo) (int, *TurnInfo) {
 
 	now := time.Now().UnixMilli()
 
-	// Get the configured game name
+	// Get the configured game name and profile
 	gameName := strings.ToLower(cfg.GameName)
+	profile := cfg.Profile()
 
 	// Track current files to detect deleted ones
 	currentFiles := make(map[string]bool)
 
 	// Read all files in directory
-	files, err := os.ReadDir(dirPath)
+	files, err := st.List()
 	if err != nil {
 		fmt.Printf("❌ Error reading directory: %v\n", err)
 		return currentTurn, currentTurnInfo
 	}
 
 	// Process each file
-	for _, file := range files {
-		if file.IsDir() {
-			continue
-		}
-
+	for _, name := range files {
 		// Skip resign files from normal processing
-		if uname, ok := matchResignUsername(file.Name(), cfg.GameName, userMappings); ok {
-			lf := strings.ToLower(file.Name())
+		if uname, ok := matchResignUsername(name, cfg.GameName, userMappings); ok {
+			lf := strings.ToLower(name)
 			if _, exists := fileTracker[lf]; !exists {
 				fileTracker[lf] = &FileTrackingInfo{FirstSeen: now, Processed: true}
 			}
-			fmt.Printf("🚪 Resignation file detected for user %s: %s (ignored for save processing)\n", uname, file.Name())
+			fmt.Printf("🚪 Resignation file detected for user %s: %s (ignored for save processing)\n", uname, name)
+			continue
+		}
+
+		filename := strings.ToLower(name)
+
+		// Sync tools' transfer and lock files are never saves
+		if _, ok := syncfiles.Temp(name); ok {
+			continue
+		}
+
+		// Report conflict copies of a save once, leaving the save itself to normal processing
+		if c, ok := syncfiles.ParseConflict(name); ok {
+			original := strings.ToLower(c.Original)
+			if !isSaveCandidate(original, cfg) || belongsToOtherGame(original, cfg) {
+				continue
+			}
+			currentFiles[filename] = true
+			if _, exists := fileTracker[filename]; !exists {
+				fileTracker[filename] = &FileTrackingInfo{FirstSeen: now, Processed: true}
+				fmt.Printf("🔀 %s conflict copy detected: %s (competes with %s)\n", c.Tool, name, c.Original)
+				reportSyncConflict(st, c, files, userMappings, cfg)
+			}
 			continue
 		}
 
-		filename := strings.ToLower(file.Name())
-		// Only process allowed extensions
-		if !hasAllowedExtension(filename, cfg.AllowedExtensions) {
+		// Only process allowed extensions, skipping files the game writes on its own
+		// and saves of other games sharing the directory
+		if !isSaveCandidate(filename, cfg) || belongsToOtherGame(filename, cfg) {
 			continue
 		}
 		currentFiles[filename] = true
 
 		// Try to extract turn number from filename
-		if turnNumber := extractTurnNumber(filename); turnNumber > currentTurn {
+		if turnNumber := profile.TurnNumber(filename); turnNumber > currentTurn {
 			currentTurn = turnNumber
 			fmt.Printf("🔢 Updated current turn to %d based on filename: %s\n", currentTurn, filename)
 		}
 
-		// get file size for debounce improvement
-		var size int64 = 0
-		if fi, err := os.Stat(filepath.Join(dirPath, file.Name())); err == nil {
-			size = fi.Size()
+		// Saves already handled need no further checks
+		info, exists := fileTracker[filename]
+		if exists && info.Processed {
+			continue
+		}
+
+		// Size and modification time feed the stability check
+		fi, err := st.Stat(name)
+		if err != nil {
+			fmt.Printf("❌ Error checking file %s: %v\n", filename, err)
+			continue
 		}
+		size := fi.Size
 
-		if info, exists := fileTracker[filename]; !exists {
+		if !exists {
 			// New file detected
 			fmt.Printf("📄 New save file detected: %s, starting debounce period\n", filename)
 			fileTracker[filename] = &FileTrackingInfo{
 				FirstSeen: now,
 				Processed: false,
 				LastSize:  size,
+				ModTime:   fi.ModTime.UnixNano(),
+				ETag:      fi.ETag,
 			}
-		} else if !info.Processed && (now-info.FirstSeen) >= int64(fileDebounceMs) {
-			// File has been stable for debounce period
-			// Ensure the file size hasn't changed since first seen
-			if size != 0 && info.LastSize != 0 && size != info.LastSize {
-				// Update size and extend debounce window
-				info.LastSize = size
-				info.FirstSeen = now
-				fmt.Printf("⏳ File %s size changed, extending debounce window\n", filename)
-				continue
+		} else if info.Synced || (now-info.FirstSeen) >= fileDebounce.Milliseconds() {
+			if info.Synced {
+				// Syncthing has finished writing the file, so there is nothing to wait for
+				hash, err := hashFile(st, name)
+				if err != nil {
+					fmt.Printf("❌ Error hashing file %s: %v\n", filename, err)
+					continue
+				}
+				info.LastSize, info.ModTime, info.ETag, info.Hash = size, fi.ModTime.UnixNano(), fi.ETag, hash
+				fmt.Printf("🔗 File %s fully synced by Syncthing, processing now\n", filename)
+			} else {
+				// The debounce period is over; wait until the file stops changing
+				stable, change, err := settled(st, fi, info, now)
+				if err != nil {
+					fmt.Printf("❌ Error hashing file %s: %v\n", filename, err)
+					continue
+				}
+				if change != "" {
+					fmt.Printf("⏳ File %s %s, extending debounce window\n", filename, change)
+				}
+				if !stable {
+					continue
+				}
+				fmt.Printf("⏱️ File %s stable for %s, processing now\n", filename, duration.Format(fileDebounce))
 			}
-			fmt.Printf("⏱️ File %s stable for %ds, processing now\n", filename, fileDebounceMs/1000)
 
 			// Check if the file should be ignored
 			if shouldIgnoreFile(filename, ignorePatterns) {
@@ -467,17 +778,11 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 			}
 
 			// Check if the game name in the filename matches the configured game name
-			if !strings.HasPrefix(filename, gameName) {
+			if !profile.MatchesGame(filename, cfg.GameName) {
 				fmt.Printf("⚠️ File %s doesn't match configured game name '%s'\n", filename, gameName)
 
 				// Try to find which user *might* have saved this based on filename content
-				var foundUserIndex = -1 // Index in the userMappings slice
-				for i, mapping := range userMappings {
-					if strings.Contains(filename, strings.ToLower(mapping.Username)) {
-						foundUserIndex = i
-						break
-					}
-				}
+				foundUserIndex := findUserIndex(filename, userMappings) // Index in the userMappings slice
 
 				// Find the previous user who should be notified about the naming issue
 				if foundUserIndex != -1 {
@@ -485,9 +790,38 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 					previousUserIndex := (foundUserIndex - 1 + len(userMappings)) % len(userMappings)
 					previousUserMapping := userMappings[previousUserIndex]
 
+					// Work out the exact name the save should have had, suggesting the current
+					// turn when the filename has none
+					fileTurn := profile.TurnNumber(filename)
+					turn := fileTurn
+					if turn == 0 {
+						turn = currentTurn
+					}
+					correctedName := profile.FormatSaveName(cfg.GameName, turn, userMappings[foundUserIndex].Username) + filepath.Ext(name)
+
+					// In auto-fix mode, repair the name when the player is unambiguous and hand off as usual
+					if cfg.AutoRename != types.AutoRenameOff {
+						if reason := autoFixBlocker(st, filename, correctedName, fileTurn, userMappings, fileTracker); reason != "" {
+							fmt.Printf("🛠️ Not auto-fixing %s: %s\n", filename, reason)
+						} else if err := autoFixSave(st, name, correctedName, cfg.AutoRename); err != nil {
+							fmt.Printf("❌ Failed to auto-fix %s: %v\n", filename, err)
+						} else {
+							fmt.Printf("🛠️ Auto-fixed misnamed save %s -> %s (%s)\n", name, correctedName, cfg.AutoRename)
+							lowerCorrected := strings.ToLower(correctedName)
+							fileTracker[lowerCorrected] = &FileTrackingInfo{FirstSeen: now, Processed: true, LastSize: size, ModTime: info.ModTime, Hash: info.Hash}
+							currentFiles[lowerCorrected] = true
+							if err := webhook.SendAutoRenameWebHook(previousUserMapping.Username, previousUserMapping.DiscordID, name, correctedName, cfg.AutoRename, cfg); err != nil {
+								fmt.Printf("❌ Failed to send auto-fix notification: %v\n", err)
+							}
+							currentTurn, currentTurnInfo = handleTurnSave(lowerCorrected, userMappings, cfg, currentTurn, currentTurnInfo)
+							info.Processed = true
+							continue
+						}
+					}
+
 					fmt.Printf("🔔 Sending rename notification to previous user %s (%s) for incorrectly named file %s\n",
 						previousUserMapping.Username, maskID(previousUserMapping.DiscordID), filename)
-					webhook.SendRenameWebHook(previousUserMapping.Username, previousUserMapping.DiscordID, filename, currentTurn, cfg)
+					webhook.SendRenameWebHook(previousUserMapping.Username, previousUserMapping.DiscordID, filename, correctedName, cfg)
 
 				} else {
 					fmt.Printf("❓ Cannot identify any user for incorrectly named file: %s. Cannot determine who to notify.\n", filename)
@@ -497,63 +831,8 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 				continue
 			}
 
-			// Find username in filename to identify the player whose turn it *is*
-			var currentPlayerIndex = -1 // Index in the userMappings slice
-			for i, mapping := range userMappings {
-				// Check if the filename contains the *current* player's username (case-insensitive)
-				if strings.Contains(filename, strings.ToLower(mapping.Username)) {
-					currentPlayerIndex = i
-					break
-				}
-			}
-
-			if currentPlayerIndex != -1 {
-				// The user found in the filename is the *current* player
-				currentUserMapping := userMappings[currentPlayerIndex]
-
-				// Determine the index of the *next* player in the order
-				nextPlayerIndex := (currentPlayerIndex + 1) % len(userMappings)
-				nextUserMapping := userMappings[nextPlayerIndex]
-
-				// Determine the index of the player who just finished (previous player)
-				previousPlayerIndex := (currentPlayerIndex - 1 + len(userMappings)) % len(userMappings)
-				previousUserMapping := userMappings[previousPlayerIndex]
-
-				// Determine the turn number for the *next* save file instruction
-				saveInstructionTurnNumber := currentTurn
-				// Check if the *current* player (whose file we are processing) is the last in the order.
-				// If so, the save instruction should be for the *next* turn.
-				if currentPlayerIndex == len(userMappings)-1 {
-					saveInstructionTurnNumber = currentTurn + 1
-					fmt.Printf("🔄 Last player (%s) finished turn %d, next save will start turn %d\n", currentUserMapping.Username, currentTurn, saveInstructionTurnNumber)
-					// Update the main turn counter *after* processing this file and determining the instruction number
-					currentTurn = saveInstructionTurnNumber
-				}
-
-				fmt.Printf("🔄 Turn %d: It's %s's turn (save from %s). Next up: %s (for turn %d)\n", currentTurn, currentUserMapping.Username, previousUserMapping.Username, nextUserMapping.Username, saveInstructionTurnNumber)
-
-				// Send webhook to the *current* player, instructing them to save for the *next* player, using the correct turn number for the save instruction
-				err := webhook.SendWebHook(currentUserMapping.Username, currentUserMapping.DiscordID, nextUserMapping.Username, saveInstructionTurnNumber, cfg)
-
-				if err == nil {
-					// Update the current turn info for reminder tracking
-					currentTurnInfo = &TurnInfo{
-						StartedAt:      time.Now(),
-						Username:       currentUserMapping.Username,
-						DiscordID:      currentUserMapping.DiscordID,
-						NextUsername:   nextUserMapping.Username,
-						TurnNumber:     saveInstructionTurnNumber,
-						LastRemindedAt: time.Time{}, // Zero time indicates no reminders sent yet
-					}
-
-					fmt.Printf("✅ Started tracking turn for %s (reminders will be sent if needed)\n", currentUserMapping.Username)
-				}
-
-				info.Processed = true
-			} else {
-				fmt.Printf("❓ Cannot match any user to save file: %s\n", filename)
-				info.Processed = true
-			}
+			currentTurn, currentTurnInfo = handleTurnSave(filename, userMappings, cfg, currentTurn, currentTurnInfo)
+			info.Processed = true
 		}
 	}
 
@@ -568,36 +847,89 @@ func processDirectory(dirPath string, fileTracker map[string]*FileTrackingInfo,
 	return currentTurn, currentTurnInfo
 }
 
+// handleTurnSave notifies the player named in a correctly named save file that it is their turn.
+// Returns the current turn number and turn info (possibly updated)
+func handleTurnSave(filename string, userMappings []userparser.UserMapping, cfg types.Config,
+	currentTurn int, currentTurnInfo *TurnInfo) (int, *TurnInfo) {
+	// Find username in filename to identify the player whose turn it *is*
+	// Check if the filename contains the *current* player's username or alias (case-insensitive)
+	currentPlayerIndex := findUserIndex(filename, userMappings) // Index in the userMappings slice
+
+	if currentPlayerIndex != -1 {
+		// The user found in the filename is the *current* player
+		currentUserMapping := userMappings[currentPlayerIndex]
+
+		// Determine the index of the *next* player in the order
+		nextPlayerIndex := (currentPlayerIndex + 1) % len(userMappings)
+		nextUserMapping := userMappings[nextPlayerIndex]
+
+		// Determine the index of the player who just finished (previous player)
+		previousPlayerIndex := (currentPlayerIndex - 1 + len(userMappings)) % len(userMappings)
+		previousUserMapping := userMappings[previousPlayerIndex]
+
+		// Determine the turn number for the *next* save file instruction
+		saveInstructionTurnNumber := currentTurn
+		// Check if the *current* player (whose file we are processing) is the last in the order.
+		// If so, the save instruction should be for the *next* turn.
+		if currentPlayerIndex == len(userMappings)-1 {
+			saveInstructionTurnNumber = currentTurn + 1
+			fmt.Printf("🔄 Last player (%s) finished turn %d, next save will start turn %d\n", currentUserMapping.Username, currentTurn, saveInstructionTurnNumber)
+			// Update the main turn counter *after* processing this file and determining the instruction number
+			currentTurn = saveInstructionTurnNumber
+		}
+
+		fmt.Printf("🔄 Turn %d: It's %s's turn (save from %s). Next up: %s (for turn %d)\n", currentTurn, currentUserMapping.Username, previousUserMapping.Username, nextUserMapping.Username, saveInstructionTurnNumber)
+
+		// Send webhook to the *current* player, instructing them to save for the *next* player, using the correct turn number for the save instruction
+		err := webhook.SendWebHook(currentUserMapping.Username, currentUserMapping.DiscordID, nextUserMapping.Username, saveInstructionTurnNumber, cfg)
+
+		if err == nil {
+			// Update the current turn info for reminder tracking
+			currentTurnInfo = &TurnInfo{
+				StartedAt:      time.Now(),
+				Username:       currentUserMapping.Username,
+				DiscordID:      currentUserMapping.DiscordID,
+				NextUsername:   nextUserMapping.Username,
+				TurnNumber:     saveInstructionTurnNumber,
+				LastRemindedAt: time.Time{}, // Zero time indicates no reminders sent yet
+			}
+
+			fmt.Printf("✅ Started tracking turn for %s (reminders will be sent if needed)\n", currentUserMapping.Username)
+		}
+	} else {
+		fmt.Printf("❓ Cannot match any user to save file: %s\n", filename)
+	}
+
+	return currentTurn, currentTurnInfo
+}
+
 // bootstrapCurrentTurnFromExistingFiles inspects existing files to infer the current turn
 // and the current player from the most recent save. Returns a TurnInfo initialized with
 // the file's modification time as StartedAt to allow reminders to resume.
-func bootstrapCurrentTurnFromExistingFiles(dirPath string, entries []os.DirEntry, userMappings []userparser.UserMapping, cfg types.Config) (*TurnInfo, int) {
+func bootstrapCurrentTurnFromExistingFiles(st store.SaveStore, entries []string, userMappings []userparser.UserMapping, cfg types.Config) (*TurnInfo, int) {
 	var latestFile string
 	var latestMod time.Time
 
-	gameName := strings.ToLower(cfg.GameName)
+	profile := cfg.Profile()
 
 	// Find the most recently modified valid file
 	for _, e := range entries {
-		if e.IsDir() {
-			continue
-		}
-		name := strings.ToLower(e.Name())
-		if !hasAllowedExtension(name, cfg.AllowedExtensions) {
+		name := strings.ToLower(e)
+		if !isSaveCandidate(name, cfg) || belongsToOtherGame(name, cfg) {
 			continue
 		}
 		if len(cfg.IgnorePatterns) > 0 && shouldIgnoreFile(name, cfg.IgnorePatterns) {
 			continue
 		}
-		if !strings.HasPrefix(name, gameName) {
+		if !profile.MatchesGame(name, cfg.GameName) {
 			continue
 		}
-		fi, err := os.Stat(filepath.Join(dirPath, e.Name()))
+		fi, err := st.Stat(e)
 		if err != nil {
 			continue
 		}
-		if fi.ModTime().After(latestMod) {
-			latestMod = fi.ModTime()
+		if fi.ModTime.After(latestMod) {
+			latestMod = fi.ModTime
 			latestFile = name
 		}
 	}
@@ -607,16 +939,10 @@ func bootstrapCurrentTurnFromExistingFiles(dirPath string, entries []os.DirEntry
 	}
 
 	// Infer turn number
-	inferredTurn := extractTurnNumber(latestFile)
+	inferredTurn := profile.TurnNumber(latestFile)
 
 	// Determine current player from filename
-	currentPlayerIndex := -1
-	for i, m := range userMappings {
-		if strings.Contains(latestFile, strings.ToLower(m.Username)) {
-			currentPlayerIndex = i
-			break
-		}
-	}
+	currentPlayerIndex := findUserIndex(latestFile, userMappings)
 	if currentPlayerIndex == -1 {
 		return nil, inferredTurn
 	}
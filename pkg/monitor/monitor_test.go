@@ -0,0 +1,249 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+const (
+	aliceID = "123456789012345678"
+	bobID   = "223456789012345678"
+	carolID = "323456789012345678"
+)
+
+// hookRecorder stands in for the Discord webhook and records every message posted to it
+type hookRecorder struct {
+	mu       sync.Mutex
+	messages []types.DiscordWebhook
+}
+
+func newHookRecorder(t *testing.T) (*hookRecorder, string) {
+	t.Helper()
+	h := &hookRecorder{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var msg types.DiscordWebhook
+		if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+			t.Errorf("webhook payload: %v", err)
+		}
+		h.mu.Lock()
+		h.messages = append(h.messages, msg)
+		h.mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(srv.Close)
+	return h, srv.URL
+}
+
+// take returns the messages posted since the last call
+func (h *hookRecorder) take() []types.DiscordWebhook {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	msgs := h.messages
+	h.messages = nil
+	return msgs
+}
+
+// testGame is a three-player game played through an in-memory store
+type testGame struct {
+	t       *testing.T
+	st      *store.Memory
+	cfg     types.Config
+	users   []userparser.UserMapping
+	hook    *hookRecorder
+	tracker map[string]*FileTrackingInfo
+	turn    int
+	info    *TurnInfo
+}
+
+func newTestGame(t *testing.T) *testGame {
+	t.Helper()
+	users, err := userparser.ParseUsersFromString("1 Alice " + aliceID + ",2 Bob " + bobID + ",3 Carol " + carolID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hook, url := newHookRecorder(t)
+	return &testGame{
+		t:     t,
+		st:    store.NewMemory(),
+		users: users,
+		hook:  hook,
+		cfg: types.Config{
+			GameName:          "pbem1",
+			WebhookURL:        url,
+			AllowedExtensions: []string{"se1"},
+			AutoRename:        types.AutoRenameOff,
+		},
+		tracker: make(map[string]*FileTrackingInfo),
+		turn:    1,
+	}
+}
+
+// scan runs one pass of the monitor over the store with the players who haven't resigned
+func (g *testGame) scan() {
+	active := filterUserMappings(g.users, scanResignations(g.st, g.cfg.GameName, g.users))
+	g.turn, g.info = processDirectory(g.st, g.tracker, active, g.cfg.FileDebounce, g.cfg.IgnorePatterns, g.cfg, g.turn, g.info)
+}
+
+// settle scans until a new save has been seen, debounced and confirmed unchanged
+func (g *testGame) settle() {
+	for i := 0; i < 3; i++ {
+		g.scan()
+	}
+}
+
+// expectPings checks that the messages posted since the last check mention exactly ids, in order
+func (g *testGame) expectPings(ids ...string) []types.DiscordWebhook {
+	g.t.Helper()
+	msgs := g.hook.take()
+	if len(msgs) != len(ids) {
+		g.t.Fatalf("posted %d messages, want %d: %+v", len(msgs), len(ids), msgs)
+	}
+	for i, id := range ids {
+		if !strings.Contains(msgs[i].Content, "<@"+id+">") {
+			g.t.Errorf("message %d = %q, want a ping for %s", i, msgs[i].Content, id)
+		}
+	}
+	return msgs
+}
+
+// instructions returns the save instructions of a turn notification
+func instructions(msg types.DiscordWebhook) string {
+	if len(msg.Embeds) == 0 || len(msg.Embeds[0].Fields) == 0 {
+		return ""
+	}
+	return msg.Embeds[0].Fields[0].Value
+}
+
+func TestTurnHandoff(t *testing.T) {
+	g := newTestGame(t)
+	modTime := time.Now()
+
+	// Each save is named for the player whose turn it now is
+	for _, step := range []struct {
+		save, pinged, nextSave string
+		turn                   int
+	}{
+		{"pbem1_turn1_Bob.se1", bobID, "pbem1_turn1_Carol", 1},
+		{"pbem1_turn1_Carol.se1", carolID, "pbem1_turn2_Alice", 2},
+		{"pbem1_turn2_Alice.se1", aliceID, "pbem1_turn2_Bob", 2},
+	} {
+		g.st.Put(step.save, []byte("save of "+step.save), modTime)
+		g.settle()
+		msgs := g.expectPings(step.pinged)
+		if !strings.Contains(instructions(msgs[0]), step.nextSave) {
+			t.Errorf("%s: instructions = %q, want them to name %s", step.save, instructions(msgs[0]), step.nextSave)
+		}
+		if g.turn != step.turn || g.info == nil || g.info.DiscordID != step.pinged {
+			t.Errorf("%s: turn = %d, tracking %+v; want turn %d for %s", step.save, g.turn, g.info, step.turn, step.pinged)
+		}
+	}
+
+	// Processed saves are not announced again
+	g.settle()
+	g.expectPings()
+}
+
+func TestResignedPlayerIsSkipped(t *testing.T) {
+	g := newTestGame(t)
+	g.st.Put("pbem1_resign_Bob.se1", nil, time.Now())
+
+	g.st.Put("pbem1_turn1_Alice.se1", []byte("save"), time.Now())
+	g.settle()
+	msgs := g.expectPings(aliceID)
+	if !strings.Contains(instructions(msgs[0]), "pbem1_turn1_Carol") {
+		t.Errorf("instructions = %q, want Bob skipped for Carol", instructions(msgs[0]))
+	}
+	if info := g.tracker["pbem1_resign_bob.se1"]; info == nil || !info.Processed {
+		t.Errorf("resign file tracked as %+v, want it marked processed", info)
+	}
+}
+
+func TestDebounceWaitsForStableSave(t *testing.T) {
+	g := newTestGame(t)
+	g.cfg.FileDebounce = time.Hour
+	modTime := time.Now()
+
+	// Nothing is processed within the debounce period
+	g.st.Put("pbem1_turn1_Bob.se1", []byte("partial"), modTime)
+	g.settle()
+	g.expectPings()
+
+	// Once it is over, the file must look the same on two scans in a row
+	g.cfg.FileDebounce = 0
+	g.scan() // records the hash
+	g.st.Put("pbem1_turn1_Bob.se1", []byte("PARTIAL"), modTime)
+	g.scan() // same size and time, other contents
+	g.expectPings()
+	if info := g.tracker["pbem1_turn1_bob.se1"]; info == nil || info.Processed {
+		t.Fatalf("save rewritten in place was processed: %+v", info)
+	}
+
+	g.st.Put("pbem1_turn1_Bob.se1", []byte("complete save"), modTime.Add(time.Second))
+	g.scan() // size and time changed
+	g.expectPings()
+
+	g.scan() // hashed
+	g.expectPings()
+	g.scan() // confirmed
+	g.expectPings(bobID)
+}
+
+func TestMisnamedSave(t *testing.T) {
+	g := newTestGame(t)
+	g.st.Put("mygame_turn1_Bob.se1", []byte("save"), time.Now())
+	g.settle()
+
+	// The player before Bob saved it, so Alice is asked to rename it
+	msgs := g.expectPings(aliceID)
+	if !strings.Contains(instructions(msgs[0]), "pbem1_turn1_Bob.se1") {
+		t.Errorf("rename instructions = %q, want the corrected name", instructions(msgs[0]))
+	}
+	if g.info != nil {
+		t.Errorf("a misnamed save started a turn: %+v", g.info)
+	}
+	if _, err := g.st.Stat("mygame_turn1_Bob.se1"); err != nil {
+		t.Errorf("misnamed save was touched with AUTO_RENAME off: %v", err)
+	}
+}
+
+func TestAutoFixHandsOff(t *testing.T) {
+	g := newTestGame(t)
+	g.cfg.AutoRename = types.AutoRenameRename
+	g.st.Put("mygame_turn1_Bob.se1", []byte("save"), time.Now())
+	g.settle()
+
+	// Alice hears about the fix and Bob gets his turn
+	g.expectPings(aliceID, bobID)
+	if _, err := g.st.Stat("pbem1_turn1_Bob.se1"); err != nil {
+		t.Errorf("corrected save missing: %v", err)
+	}
+	if _, err := g.st.Stat("mygame_turn1_Bob.se1"); err == nil {
+		t.Error("misnamed save still exists after renaming")
+	}
+	if g.info == nil || g.info.Username != "Bob" {
+		t.Errorf("tracking %+v, want Bob's turn", g.info)
+	}
+
+	// The corrected save is not processed a second time
+	g.settle()
+	g.expectPings()
+
+	// Without a turn number the save is left for a person to fix
+	g = newTestGame(t)
+	g.cfg.AutoRename = types.AutoRenameRename
+	g.st.Put("mygame_Bob.se1", []byte("save"), time.Now())
+	g.settle()
+	g.expectPings(aliceID)
+	if _, err := g.st.Stat("mygame_Bob.se1"); err != nil {
+		t.Errorf("save without a turn number was renamed: %v", err)
+	}
+}
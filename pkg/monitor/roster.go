@@ -0,0 +1,132 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/discordapi"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// rosterWatcher remembers the last seen state of a roster file so edits can be detected.
+type rosterWatcher struct {
+	path    string
+	modTime time.Time
+	size    int64
+	missing bool
+}
+
+// newRosterWatcher starts watching path from its current state.
+func newRosterWatcher(path string) *rosterWatcher {
+	w := &rosterWatcher{path: path}
+	w.changed()
+	return w
+}
+
+// changed reports whether the roster file was modified since the last call.
+func (w *rosterWatcher) changed() bool {
+	fi, err := os.Stat(w.path)
+	if err != nil {
+		if !w.missing {
+			log.Printf("⚠️ Roster file %s is not readable (%v); keeping the last good roster\n", w.path, err)
+			w.missing = true
+		}
+		return false
+	}
+	w.missing = false
+	if fi.ModTime().Equal(w.modTime) && fi.Size() == w.size {
+		return false
+	}
+	w.modTime = fi.ModTime()
+	w.size = fi.Size()
+	return true
+}
+
+// reload parses the roster file and returns the validated mappings.
+func (w *rosterWatcher) reload() ([]userparser.UserMapping, error) {
+	return userparser.ParseRosterFile(w.path)
+}
+
+// playersChanged reports whether a reloaded configuration lists the players differently.
+func playersChanged(old, updated types.Config) bool {
+	return old.RosterFile != updated.RosterFile || old.UserMappingsRaw != updated.UserMappingsRaw ||
+		!reflect.DeepEqual(old.Players, updated.Players)
+}
+
+// resolveDisplayNames checks every player against the Discord API when a bot token is
+// configured and records their display names. With DISCORD_GUILD_ID set, players must be
+// members of that server and their nicknames are used. Unknown user IDs are returned as an
+// error; other lookup failures are logged and the player is kept as-is.
+func resolveDisplayNames(ctx context.Context, cfg types.Config, mappings []userparser.UserMapping) error {
+	if cfg.DiscordBotToken == "" {
+		return nil
+	}
+	client := discordapi.NewClient(cfg.DiscordAPIURL, cfg.DiscordBotToken)
+	for i := range mappings {
+		name, err := client.LookupDisplayName(ctx, cfg.DiscordGuildID, mappings[i].DiscordID)
+		if errors.Is(err, discordapi.ErrUnknownUser) {
+			return fmt.Errorf("discord user %s configured for %s does not exist", maskID(mappings[i].DiscordID), mappings[i].Username)
+		}
+		if errors.Is(err, discordapi.ErrNotMember) {
+			return fmt.Errorf("discord user %s configured for %s is not a member of the server", maskID(mappings[i].DiscordID), mappings[i].Username)
+		}
+		if err != nil {
+			log.Printf("⚠️ Could not verify Discord user for %s: %v\n", mappings[i].Username, err)
+			continue
+		}
+		mappings[i].DisplayName = name
+	}
+	return nil
+}
+
+// passTurnFromRemoved hands the turn of a player who was removed from the roster to the next
+// remaining player in the previous order and notifies them. It returns the updated turn counter
+// and turn info, which is nil if no one could take over.
+func passTurnFromRemoved(info *TurnInfo, previous, active []userparser.UserMapping, currentTurn int, cfg types.Config) (int, *TurnInfo) {
+	idx := findUserIndexByName(info.Username, previous)
+	next := -1
+	for step := 1; idx != -1 && step < len(previous) && next == -1; step++ {
+		next = findUserIndexByName(previous[(idx+step)%len(previous)].Username, active)
+	}
+	if next == -1 || len(active) < 2 {
+		log.Printf("🚪 Current player %s was removed from the roster; waiting for the next save\n", info.Username)
+		return currentTurn, nil
+	}
+
+	player := active[next]
+	after := active[(next+1)%len(active)]
+	saveTurn := currentTurn
+	if next == len(active)-1 {
+		saveTurn = currentTurn + 1
+		currentTurn = saveTurn
+	}
+	log.Printf("🚪 Current player %s was removed from the roster; passing turn %d to %s\n", info.Username, currentTurn, player.Username)
+	if err := webhook.SendWebHook(player.Username, player.DiscordID, after.Username, saveTurn, cfg); err != nil {
+		log.Printf("❌ Failed to notify %s of their turn: %v\n", player.Username, err)
+		return currentTurn, nil
+	}
+	return currentTurn, &TurnInfo{
+		StartedAt:    time.Now(),
+		Username:     player.Username,
+		DiscordID:    player.DiscordID,
+		NextUsername: after.Username,
+		TurnNumber:   saveTurn,
+	}
+}
+
+// findUserIndexByName returns the index of the player with the given username, or -1
+func findUserIndexByName(username string, userMappings []userparser.UserMapping) int {
+	for i, m := range userMappings {
+		if normalize(m.Username) == normalize(username) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,146 @@
+package monitor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// waitForMessages waits until n messages have been posted and returns them
+func waitForMessages(t *testing.T, h *hookRecorder, n int) []string {
+	t.Helper()
+	var contents []string
+	deadline := time.Now().Add(5 * time.Second)
+	for len(contents) < n && time.Now().Before(deadline) {
+		for _, msg := range h.take() {
+			contents = append(contents, msg.Content)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if len(contents) != n {
+		t.Fatalf("posted %d messages, want %d: %q", len(contents), n, contents)
+	}
+	return contents
+}
+
+func TestRosterHotReload(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "pbem1_turn1_Bob.se1"), []byte("save"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hook, url := newHookRecorder(t)
+	live := types.NewLiveConfig(types.Config{
+		GameName:          "pbem1",
+		WatchDirectory:    dir,
+		WebhookURL:        url,
+		UserMappingsRaw:   "1 Alice " + aliceID + ",2 Bob " + bobID + ",3 Carol " + carolID,
+		AllowedExtensions: []string{"se1"},
+		AutoRename:        types.AutoRenameOff,
+		WatchMode:         "poll",
+		PollInterval:      20 * time.Millisecond,
+		RescanInterval:    20 * time.Millisecond,
+		ReminderInterval:  time.Hour,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- MonitorStore(ctx, live, store.NewDir(dir)) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("MonitorStore: %v", err)
+		}
+	}()
+
+	// A players.yaml created after startup replaces USER_MAPPINGS. It drops Bob, whose turn it
+	// is, so the turn passes to Carol, who saves for Alice in the next round.
+	time.Sleep(50 * time.Millisecond)
+	roster := filepath.Join(dir, "players.yaml")
+	writeRoster := func(content string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(roster, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(roster, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeRoster("players:\n"+
+		"  - {order: 1, name: Alice, discord_id: \""+aliceID+"\"}\n"+
+		"  - {order: 2, name: Carol, discord_id: \""+carolID+"\"}\n", time.Now().Add(-time.Minute))
+	msgs := waitForMessages(t, hook, 2)
+	if !strings.Contains(msgs[0], "roster") {
+		t.Errorf("first message = %q, want the roster update", msgs[0])
+	}
+	if !strings.Contains(msgs[1], "<@"+carolID+">") {
+		t.Errorf("second message = %q, want Carol to be pinged", msgs[1])
+	}
+
+	// Edits to the roster are picked up as well
+	const daveID = "423456789012345678"
+	writeRoster("players:\n"+
+		"  - {order: 1, name: Alice, discord_id: \""+aliceID+"\"}\n"+
+		"  - {order: 2, name: Carol, discord_id: \""+carolID+"\"}\n"+
+		"  - {order: 3, name: Dave, discord_id: \""+daveID+"\"}\n", time.Now())
+	if msgs := waitForMessages(t, hook, 1); !strings.Contains(msgs[0], "roster") {
+		t.Errorf("message = %q, want the roster update", msgs[0])
+	}
+
+	// An invalid edit is rejected and reported without touching the players
+	writeRoster("players: [oops", time.Now().Add(time.Minute))
+	waitForMessages(t, hook, 1)
+
+	// Carol's save is for Alice, who is pinged as usual
+	if err := os.WriteFile(filepath.Join(dir, "pbem1_turn2_Alice.se1"), []byte("save"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if msgs := waitForMessages(t, hook, 1); !strings.Contains(msgs[0], "<@"+aliceID+">") {
+		t.Errorf("message = %q, want Alice to be pinged", msgs[0])
+	}
+}
+
+func TestPassTurnFromRemoved(t *testing.T) {
+	g := newTestGame(t)
+	tests := []struct {
+		name             string
+		removed          string
+		active           []string
+		turn             int
+		want             string
+		wantTurn, saveTo int
+	}{
+		{"middle player", "Bob", []string{"Alice", "Carol"}, 1, "Carol", 2, 2},
+		{"last player", "Carol", []string{"Alice", "Bob"}, 2, "Alice", 2, 2},
+		{"first player", "Alice", []string{"Bob", "Carol"}, 1, "Bob", 1, 1},
+		{"too few players", "Bob", []string{"Alice"}, 1, "", 1, 0},
+	}
+	byName := map[string]int{"Alice": 0, "Bob": 1, "Carol": 2}
+	for _, tt := range tests {
+		var active []userparser.UserMapping
+		for _, name := range tt.active {
+			active = append(active, g.users[byName[name]])
+		}
+		info := &TurnInfo{Username: tt.removed, TurnNumber: tt.turn}
+		turn, got := passTurnFromRemoved(info, g.users, active, tt.turn, g.cfg)
+		if turn != tt.wantTurn {
+			t.Errorf("%s: turn = %d, want %d", tt.name, turn, tt.wantTurn)
+		}
+		if tt.want == "" {
+			if got != nil {
+				t.Errorf("%s: turn passed to %s, want nobody", tt.name, got.Username)
+			}
+			continue
+		}
+		if got == nil || got.Username != tt.want || got.TurnNumber != tt.saveTo {
+			t.Errorf("%s: got %+v, want %s saving for turn %d", tt.name, got, tt.want, tt.saveTo)
+		}
+		g.hook.take()
+	}
+}
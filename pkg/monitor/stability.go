@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+)
+
+// hashFile returns the hex SHA-256 of a file's contents, reading it as a stream
+func hashFile(st store.SaveStore, name string) (string, error) {
+	f, err := st.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// settled records an observation of a pending file whose debounce period is over and reports
+// whether it is stable: its size, modification time and content hash must match the previous
+// observation. This catches sync tools that preallocate the full size or write in place.
+// Stores with change tokens (ETags) compare those instead and download the file only once.
+// Any change restarts the debounce period; the message explains what changed.
+func settled(st store.SaveStore, fi store.FileInfo, info *FileTrackingInfo, now int64) (bool, string, error) {
+	size, modTime := fi.Size, fi.ModTime.UnixNano()
+	if size != info.LastSize || modTime != info.ModTime || fi.ETag != info.ETag {
+		info.LastSize, info.ModTime, info.ETag, info.Hash = size, modTime, fi.ETag, ""
+		info.FirstSeen = now
+		return false, "size or modification time changed", nil
+	}
+
+	// An unchanged ETag since the file was first seen means unchanged contents
+	if fi.ETag != "" {
+		hash, err := hashFile(st, fi.Name)
+		if err != nil {
+			return false, "", err
+		}
+		info.Hash = hash
+		return true, "", nil
+	}
+
+	hash, err := hashFile(st, fi.Name)
+	if err != nil {
+		return false, "", err
+	}
+	switch info.Hash {
+	case hash:
+		return true, "", nil
+	case "":
+		// First full observation; confirm it on the next scan
+		info.Hash = hash
+		return false, "", nil
+	default:
+		info.Hash = hash
+		info.FirstSeen = now
+		return false, "contents changed", nil
+	}
+}
@@ -0,0 +1,65 @@
+package monitor
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// Status is a snapshot of one game's monitor state, as reported by the status endpoint
+type Status struct {
+	ID             string     `json:"id,omitempty"`
+	GameName       string     `json:"game_name"`
+	WatchDirectory string     `json:"watch_directory"`
+	CurrentTurn    int        `json:"current_turn"`
+	CurrentPlayer  string     `json:"current_player,omitempty"`
+	NextPlayer     string     `json:"next_player,omitempty"`
+	TurnStartedAt  *time.Time `json:"turn_started_at,omitempty"`
+	ActivePlayers  []string   `json:"active_players"`
+	LastScan       time.Time  `json:"last_scan"`
+}
+
+var (
+	statusMu sync.RWMutex
+	statuses = make(map[string]Status)
+)
+
+// publishStatus records the latest state of a game's monitor loop
+func publishStatus(cfg types.Config, currentTurn int, info *TurnInfo, activeMappings []userparser.UserMapping) {
+	st := Status{
+		ID:             cfg.ID,
+		GameName:       cfg.GameName,
+		WatchDirectory: cfg.WatchDirectory,
+		CurrentTurn:    currentTurn,
+		ActivePlayers:  make([]string, 0, len(activeMappings)),
+		LastScan:       time.Now(),
+	}
+	for _, m := range activeMappings {
+		st.ActivePlayers = append(st.ActivePlayers, m.Username)
+	}
+	if info != nil {
+		started := info.StartedAt
+		st.CurrentPlayer = info.Username
+		st.NextPlayer = info.NextUsername
+		st.TurnStartedAt = &started
+	}
+
+	statusMu.Lock()
+	statuses[cfg.ID] = st
+	statusMu.Unlock()
+}
+
+// Statuses returns the latest state of every game hosted by this process, ordered by ID
+func Statuses() []Status {
+	statusMu.RLock()
+	defer statusMu.RUnlock()
+	out := make([]Status, 0, len(statuses))
+	for _, st := range statuses {
+		out = append(out, st)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
@@ -0,0 +1,78 @@
+package monitor
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncfiles"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncthing"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// handleSyncthingUpdate applies an update from Syncthing and reports whether the directory should be scanned.
+// Saves Syncthing has finished syncing are marked so they are processed without waiting out the debounce period.
+func handleSyncthingUpdate(u syncthing.Update, st store.SaveStore, fileTracker map[string]*FileTrackingInfo, userMappings []userparser.UserMapping, cfg types.Config) bool {
+	switch u.Kind {
+	case syncthing.UpdateItem:
+		// Only files directly in the watch directory can be saves
+		if strings.ContainsAny(u.Item, `/\`) {
+			return false
+		}
+		// Resign files and conflict copies are left to the scan this triggers; anything else
+		// processDirectory skips must not get a tracker entry either
+		if _, ok := matchResignUsername(u.Item, cfg.GameName, userMappings); ok {
+			return true
+		}
+		if _, temp := syncfiles.Temp(u.Item); temp {
+			return false
+		}
+		if _, ok := syncfiles.ParseConflict(u.Item); ok {
+			return true
+		}
+		filename := strings.ToLower(u.Item)
+		if !isSaveCandidate(filename, cfg) || belongsToOtherGame(filename, cfg) || shouldIgnoreFile(filename, cfg.IgnorePatterns) {
+			return false
+		}
+		if _, err := st.Stat(u.Item); err != nil {
+			return false
+		}
+		info, exists := fileTracker[filename]
+		switch {
+		case !exists:
+			fmt.Printf("📄 New save file synced by Syncthing: %s\n", filename)
+			fileTracker[filename] = &FileTrackingInfo{FirstSeen: time.Now().UnixMilli(), Synced: true}
+		case !info.Processed:
+			fmt.Printf("🔗 Syncthing finished syncing %s\n", filename)
+			info.Synced = true
+		default:
+			return false
+		}
+		return true
+	case syncthing.UpdateCompletion:
+		// A peer caught up or fell behind; pick up anything that changed meanwhile
+		return true
+	case syncthing.UpdatePeer:
+		if u.Peer.Connected {
+			log.Printf("📶 %sSyncthing peer %s reconnected\n", cfg.Label(), u.Peer.Label())
+			return false
+		}
+		log.Printf("📴 %sSyncthing peer %s is disconnected; saves from it won't arrive until it reconnects\n", cfg.Label(), u.Peer.Label())
+		var adminIDs []string
+		for _, m := range userMappings {
+			if m.Admin {
+				adminIDs = append(adminIDs, m.DiscordID)
+			}
+		}
+		title := fmt.Sprintf("Syncthing peer %s is disconnected from %s", u.Peer.Label(), cfg.GameName)
+		details := fmt.Sprintf("Device %s shares the watch directory but is not connected. Saves made on it won't reach the bot until it reconnects.", u.Peer.ID)
+		if err := webhook.SendAdminAlertWebHook(title, details, adminIDs, cfg); err != nil {
+			log.Printf("❌ Failed to send Syncthing peer alert: %v\n", err)
+		}
+	}
+	return false
+}
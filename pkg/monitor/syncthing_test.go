@@ -0,0 +1,52 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/syncthing"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+func TestHandleSyncthingItem(t *testing.T) {
+	users, err := userparser.ParseUsersFromString("1 Alice 123456789012345678,2 Bob 223456789012345678")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := types.Config{
+		GameName:          "pbem1",
+		AllowedExtensions: []string{"se1"},
+		IgnorePatterns:    []string{"draft"},
+		ForeignGameNames:  []string{"pbem10"},
+	}
+	st := store.NewMemory()
+
+	tests := []struct {
+		item      string
+		wantScan  bool
+		wantEntry bool
+	}{
+		{"pbem1_turn3_Bob.se1", true, true},
+		{"pbem1_turn3_Bob.sync-conflict-20260301-120000-ABCDEFG.se1", true, false},
+		{"resign_alice.se1", true, false},
+		{"pbem10_turn3_Bob.se1", false, false},
+		{"pbem1_turn3_Bob_draft.se1", false, false},
+		{".syncthing.pbem1_turn3_Bob.se1.tmp", false, false},
+		{"notes.txt", false, false},
+		{"sub/pbem1_turn3_Bob.se1", false, false},
+	}
+	for _, tt := range tests {
+		st.Put(tt.item, []byte("save"), time.Now())
+		tracker := make(map[string]*FileTrackingInfo)
+		scan := handleSyncthingUpdate(syncthing.Update{Kind: syncthing.UpdateItem, Item: tt.item}, st, tracker, users, cfg)
+		if scan != tt.wantScan {
+			t.Errorf("%s: scan = %v, want %v", tt.item, scan, tt.wantScan)
+		}
+		if info, ok := tracker[strings.ToLower(tt.item)]; ok != tt.wantEntry || (ok && !info.Synced) {
+			t.Errorf("%s: tracker entry = %+v, want entry %v", tt.item, info, tt.wantEntry)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// Package redact keeps secrets such as webhook URLs and bot tokens out of log output.
+package redact
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Placeholder replaces secrets in redacted output.
+const Placeholder = "[REDACTED]"
+
+// minSecretLen keeps short values from redacting unrelated text
+const minSecretLen = 8
+
+var (
+	mu       sync.RWMutex
+	secrets  = map[string]bool{}
+	replacer = strings.NewReplacer()
+)
+
+// Register adds a secret to redact from all output. For URLs the path and query,
+// which carry webhook tokens, are registered as well.
+func Register(secret string) {
+	secret = strings.TrimSpace(secret)
+	if len(secret) < minSecretLen {
+		return
+	}
+	values := []string{secret}
+	if u, err := url.Parse(secret); err == nil && u.Host != "" {
+		if len(u.Path) >= minSecretLen {
+			values = append(values, u.Path)
+		}
+		if len(u.RawQuery) >= minSecretLen {
+			values = append(values, u.RawQuery)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for _, v := range values {
+		secrets[v] = true
+	}
+
+	// Replace longer secrets first so a URL is not left half-redacted by its path
+	list := make([]string, 0, len(secrets))
+	for s := range secrets {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return len(list[i]) > len(list[j]) })
+	pairs := make([]string, 0, len(list)*2)
+	for _, s := range list {
+		pairs = append(pairs, s, Placeholder)
+	}
+	replacer = strings.NewReplacer(pairs...)
+}
+
+// String returns s with every registered secret replaced.
+func String(s string) string {
+	mu.RLock()
+	r := replacer
+	mu.RUnlock()
+	return r.Replace(s)
+}
+
+// Error strips the request URL from *url.Error values, which net/http includes in
+// error messages, and redacts registered secrets from the rest. Errors wrapping a
+// *url.Error, or mentioning a secret, keep their chain for errors.Is and errors.As.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+	if uerr, ok := err.(*url.Error); ok {
+		stripped := *uerr
+		stripped.URL = stripURL(uerr.URL)
+		stripped.Err = Error(uerr.Err)
+		return &stripped
+	}
+	msg := err.Error()
+	var uerr *url.Error
+	if errors.As(err, &uerr) {
+		msg = strings.ReplaceAll(msg, uerr.URL, stripURL(uerr.URL))
+	}
+	if msg = String(msg); msg != err.Error() {
+		return &redactedError{msg: msg, err: err}
+	}
+	return err
+}
+
+// redactedError reports a redacted message in place of the error it wraps
+type redactedError struct {
+	msg string
+	err error
+}
+
+func (e *redactedError) Error() string { return e.msg }
+func (e *redactedError) Unwrap() error { return e.err }
+
+// stripURL keeps only the scheme and host of a URL
+func stripURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return Placeholder
+	}
+	return u.Scheme + "://" + u.Host + "/" + Placeholder
+}
+
+// writer redacts everything written through it
+type writer struct {
+	w io.Writer
+}
+
+// NewWriter returns a writer that redacts registered secrets before writing to w.
+// Each write is redacted on its own, which suits the log package's one write per line.
+func NewWriter(w io.Writer) io.Writer {
+	return &writer{w: w}
+}
+
+func (rw *writer) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(rw.w, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// CaptureStdout routes os.Stdout through a pipe that redacts secrets line by line,
+// so direct fmt.Print calls are covered as well. The returned function restores
+// os.Stdout and flushes pending output; call it before exiting.
+func CaptureStdout() (restore func()) {
+	orig := os.Stdout
+	r, w, err := os.Pipe()
+	if err != nil {
+		return func() {}
+	}
+	os.Stdout = w
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		br := bufio.NewReader(r)
+		for {
+			line, err := br.ReadString('\n')
+			if line != "" {
+				io.WriteString(orig, String(line))
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			os.Stdout = orig
+			w.Close()
+			<-done
+			r.Close()
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package redact
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const (
+	webhookURL = "https://discord.com/api/webhooks/123456/abcdefTOKEN"
+	botToken   = "bot-token-0123456789"
+)
+
+func init() {
+	Register(webhookURL)
+	Register(botToken)
+	Register("short")
+}
+
+func TestString(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"posting to " + webhookURL, "posting to " + Placeholder},
+		{"path /api/webhooks/123456/abcdefTOKEN leaked", "path " + Placeholder + " leaked"},
+		{"Authorization: Bot " + botToken, "Authorization: Bot " + Placeholder},
+		{"too short to register: short", "too short to register: short"},
+		{"nothing secret", "nothing secret"},
+	}
+	for _, tt := range tests {
+		if got := String(tt.in); got != tt.want {
+			t.Errorf("String(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestError(t *testing.T) {
+	if Error(nil) != nil {
+		t.Error("Error(nil) != nil")
+	}
+
+	// net/http errors carry the request URL
+	uerr := &url.Error{Op: "Post", URL: webhookURL + "?wait=true", Err: fmt.Errorf("dial %s: timeout", botToken)}
+	got := Error(uerr)
+	if msg := got.Error(); strings.Contains(msg, "abcdefTOKEN") || strings.Contains(msg, botToken) || !strings.Contains(msg, "https://discord.com/") {
+		t.Errorf("Error(url.Error) = %q, want the host without the token or bot token", msg)
+	}
+	var stripped *url.Error
+	if !errors.As(got, &stripped) {
+		t.Errorf("Error(url.Error) returned %T, want a *url.Error", got)
+	}
+
+	// Wrapped errors keep their context and chain
+	wrapped := fmt.Errorf("sending notification: %w", &url.Error{Op: "Post", URL: "http://127.0.0.1:9/hook/some-secret-path", Err: fs.ErrNotExist})
+	got = Error(wrapped)
+	if msg := got.Error(); strings.Contains(msg, "some-secret-path") || !strings.HasPrefix(msg, "sending notification: Post") {
+		t.Errorf("Error(wrapped url.Error) = %q, want the context without the path", msg)
+	}
+	if !errors.Is(got, fs.ErrNotExist) {
+		t.Error("Error(wrapped url.Error) lost the wrapped error")
+	}
+
+	// Other errors are redacted too
+	plain := fmt.Errorf("webhook %s returned 404: %w", webhookURL, fs.ErrNotExist)
+	got = Error(plain)
+	if msg := got.Error(); msg != "webhook "+Placeholder+" returned 404: file does not exist" {
+		t.Errorf("Error(plain) = %q", msg)
+	}
+	if !errors.Is(got, fs.ErrNotExist) {
+		t.Error("Error(plain) lost the wrapped error")
+	}
+
+	// Errors without secrets are returned as they are
+	if clean := errors.New("nothing secret"); Error(clean) != clean {
+		t.Error("Error changed an error without secrets")
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter(&buf)
+	line := "token " + botToken + "\n"
+	n, err := io.WriteString(w, line)
+	if err != nil || n != len(line) {
+		t.Errorf("Write = %d, %v; want %d, nil", n, err, len(line))
+	}
+	if buf.String() != "token "+Placeholder+"\n" {
+		t.Errorf("wrote %q", buf.String())
+	}
+}
+
+func TestCaptureStdout(t *testing.T) {
+	// Stand in for the terminal with a file
+	out, err := os.Create(filepath.Join(t.TempDir(), "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer out.Close()
+	orig := os.Stdout
+	os.Stdout = out
+	defer func() { os.Stdout = orig }()
+
+	restore := CaptureStdout()
+	fmt.Println("webhook:", webhookURL)
+	fmt.Print("no newline at the end: " + botToken)
+	restore()
+	restore() // safe to call twice
+
+	if os.Stdout != out {
+		t.Error("restore did not put back os.Stdout")
+	}
+	data, err := os.ReadFile(out.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "webhook: " + Placeholder + "\nno newline at the end: " + Placeholder
+	if string(data) != want {
+		t.Errorf("captured %q, want %q", data, want)
+	}
+}
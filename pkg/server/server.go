@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/health"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/monitor"
+)
+
+// Start serves the HTTP endpoints shared by all games on addr until ctx is canceled.
+func Start(ctx context.Context, addr string) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", handleStatus)
+	mux.HandleFunc("GET /livez", handleLivez)
+	mux.HandleFunc("GET /readyz", handleReadyz)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		srv.Shutdown(shutdownCtx)
+	}()
+
+	go func() {
+		log.Printf("🌐 HTTP server listening on %s\n", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Printf("❌ HTTP server stopped: %v\n", err)
+		}
+	}()
+}
+
+// handleStatus reports the turn state of every game
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{"games": monitor.Statuses()})
+}
+
+// handleLivez fails when a monitor loop has stopped polling
+func handleLivez(w http.ResponseWriter, r *http.Request) {
+	rep := health.Evaluate(health.States(), time.Now())
+	code := http.StatusOK
+	if !rep.Live {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, rep)
+}
+
+// handleReadyz fails until every monitor is polling a readable watch directory
+func handleReadyz(w http.ResponseWriter, r *http.Request) {
+	rep := health.Evaluate(health.States(), time.Now())
+	code := http.StatusOK
+	if !rep.Ready {
+		code = http.StatusServiceUnavailable
+	}
+	writeJSON(w, code, rep)
+}
+
+func writeJSON(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.Encode(v)
+}
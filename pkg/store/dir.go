@@ -0,0 +1,83 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Dir is a SaveStore backed by a local directory.
+type Dir struct {
+	Path string
+}
+
+// NewDir returns a store for the files in path.
+func NewDir(path string) *Dir {
+	return &Dir{Path: path}
+}
+
+// List returns the names of the regular files in the directory.
+func (d *Dir) List() ([]string, error) {
+	entries, err := os.ReadDir(d.Path)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
+
+// Stat describes a file in the directory.
+func (d *Dir) Stat(name string) (FileInfo, error) {
+	fi, err := os.Stat(filepath.Join(d.Path, name))
+	if err != nil {
+		return FileInfo{}, err
+	}
+	return FileInfo{Name: name, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+// Open opens a file in the directory for reading.
+func (d *Dir) Open(name string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(d.Path, name))
+}
+
+// Rename renames a file within the directory.
+func (d *Dir) Rename(oldName, newName string) error {
+	return os.Rename(filepath.Join(d.Path, oldName), filepath.Join(d.Path, newName))
+}
+
+// Write writes to a hidden temporary file first, so the directory never shows a partial file.
+// A temporary file left behind by an interrupted write is replaced.
+func (d *Dir) Write(name string, r io.Reader, size int64, modTime time.Time) error {
+	tmp := filepath.Join(d.Path, "."+name+".tmp")
+	if err := os.Remove(tmp); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	out, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o666)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, r); err != nil {
+		out.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if !modTime.IsZero() {
+		if err := os.Chtimes(tmp, modTime, modTime); err != nil {
+			os.Remove(tmp)
+			return err
+		}
+	}
+	return os.Rename(tmp, filepath.Join(d.Path, name))
+}
@@ -0,0 +1,70 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDirWriteReplacesStaleTemp(t *testing.T) {
+	dir := t.TempDir()
+	d := NewDir(dir)
+
+	// A write interrupted by a crash leaves its temporary file behind
+	stale := filepath.Join(dir, ".pbem1_turn3_Alice.se1.tmp")
+	if err := os.WriteFile(stale, []byte("partial"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	modTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	if err := d.Write("pbem1_turn3_Alice.se1", strings.NewReader("save data"), 9, modTime); err != nil {
+		t.Fatalf("Write after a crashed write: %v", err)
+	}
+	if _, err := os.Stat(stale); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("temporary file still present: %v", err)
+	}
+
+	fi, err := d.Stat("pbem1_turn3_Alice.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Size != int64(len("save data")) || !fi.ModTime.Equal(modTime) {
+		t.Errorf("Stat = %+v", fi)
+	}
+	r, err := d.Open("pbem1_turn3_Alice.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "save data" {
+		t.Errorf("contents = %q", data)
+	}
+}
+
+func TestDirListAndRename(t *testing.T) {
+	dir := t.TempDir()
+	d := NewDir(dir)
+	os.WriteFile(filepath.Join(dir, "a.se1"), nil, 0o644)
+	os.Mkdir(filepath.Join(dir, "backup"), 0o755)
+
+	if err := d.Rename("a.se1", "b.se1"); err != nil {
+		t.Fatal(err)
+	}
+	names, err := d.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(names, []string{"b.se1"}) {
+		t.Errorf("List = %v, want only the file", names)
+	}
+	if _, err := d.Stat("a.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of the old name: err = %v, want fs.ErrNotExist", err)
+	}
+}
@@ -0,0 +1,101 @@
+package store
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Memory is a SaveStore that keeps files in memory, for driving the monitor without a disk.
+// It is safe for concurrent use.
+type Memory struct {
+	mu    sync.Mutex
+	files map[string]memFile
+}
+
+type memFile struct {
+	data    []byte
+	modTime time.Time
+}
+
+// NewMemory returns an empty in-memory store.
+func NewMemory() *Memory {
+	return &Memory{files: make(map[string]memFile)}
+}
+
+// Put adds or replaces a file. A zero modTime means now.
+func (m *Memory) Put(name string, data []byte, modTime time.Time) {
+	if modTime.IsZero() {
+		modTime = time.Now()
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.files[name] = memFile{data: append([]byte(nil), data...), modTime: modTime}
+}
+
+// Remove deletes a file if it exists.
+func (m *Memory) Remove(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.files, name)
+}
+
+// List returns the file names in sorted order.
+func (m *Memory) List() ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	names := make([]string, 0, len(m.files))
+	for name := range m.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Stat describes a file.
+func (m *Memory) Stat(name string) (FileInfo, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return FileInfo{}, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+	}
+	return FileInfo{Name: name, Size: int64(len(f.data)), ModTime: f.modTime}, nil
+}
+
+// Open returns a reader over a snapshot of the file's contents.
+func (m *Memory) Open(name string) (io.ReadCloser, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	return io.NopCloser(bytes.NewReader(f.data)), nil
+}
+
+// Rename moves a file to a new name.
+func (m *Memory) Rename(oldName, newName string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	f, ok := m.files[oldName]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrNotExist}
+	}
+	delete(m.files, oldName)
+	m.files[newName] = f
+	return nil
+}
+
+// Write stores the contents of r under name.
+func (m *Memory) Write(name string, r io.Reader, size int64, modTime time.Time) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.Put(name, data, modTime)
+	return nil
+}
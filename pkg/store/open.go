@@ -0,0 +1,106 @@
+package store
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Options holds the settings of remote stores.
+type Options struct {
+	S3Endpoint  string
+	S3Region    string
+	S3AccessKey string
+	S3SecretKey string
+
+	WebDAVUsername string
+	WebDAVPassword string
+}
+
+// defaultS3Region is used when no region is configured
+const defaultS3Region = "us-east-1"
+
+// remoteSchemes are the URL schemes of remote stores
+var remoteSchemes = []string{"s3://", "webdav://", "webdavs://"}
+
+// IsRemote reports whether location names a remote store, such as s3://bucket/prefix or
+// webdavs://host/path, rather than a local directory.
+func IsRemote(location string) bool {
+	lower := strings.ToLower(location)
+	for _, scheme := range remoteSchemes {
+		if strings.HasPrefix(lower, scheme) {
+			return true
+		}
+	}
+	return false
+}
+
+// Open returns the store for location: a local directory, s3://bucket/prefix for a bucket,
+// or webdav://host/path (webdavs:// for HTTPS) for a WebDAV folder.
+// It checks the settings but does not contact the store.
+func Open(location string, opts Options) (SaveStore, error) {
+	if !IsRemote(location) {
+		return NewDir(location), nil
+	}
+	if strings.HasPrefix(strings.ToLower(location), "webdav") {
+		return openWebDAV(location, opts)
+	}
+
+	u, err := url.Parse(location)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid S3 location '%s', expected s3://bucket/prefix", location)
+	}
+	if opts.S3AccessKey == "" || opts.S3SecretKey == "" {
+		return nil, fmt.Errorf("S3_ACCESS_KEY_ID and S3_SECRET_ACCESS_KEY are required for %s", location)
+	}
+	if opts.S3Endpoint != "" {
+		if e, err := url.Parse(opts.S3Endpoint); err != nil || (e.Scheme != "http" && e.Scheme != "https") || e.Host == "" {
+			return nil, fmt.Errorf("invalid S3 endpoint '%s', expected an http or https URL", opts.S3Endpoint)
+		}
+	}
+	region := opts.S3Region
+	if region == "" {
+		region = defaultS3Region
+	}
+	prefix := strings.Trim(u.Path, "/")
+	if prefix != "" {
+		prefix += "/"
+	}
+	return &S3{
+		Endpoint:  opts.S3Endpoint,
+		Region:    region,
+		Bucket:    u.Host,
+		Prefix:    prefix,
+		AccessKey: opts.S3AccessKey,
+		SecretKey: opts.S3SecretKey,
+		HTTP:      &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+// openWebDAV returns the store for a webdav:// or webdavs:// location
+func openWebDAV(location string, opts Options) (SaveStore, error) {
+	u, err := url.Parse(location)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid WebDAV location '%s', expected webdavs://host/path", location)
+	}
+	if u.User != nil {
+		return nil, fmt.Errorf("WebDAV location '%s' must not contain credentials; use WEBDAV_USERNAME and WEBDAV_PASSWORD", u.Redacted())
+	}
+	if opts.WebDAVPassword != "" && opts.WebDAVUsername == "" {
+		return nil, fmt.Errorf("WEBDAV_USERNAME is required with WEBDAV_PASSWORD")
+	}
+	u.Scheme = "http"
+	if strings.EqualFold(strings.SplitN(location, ":", 2)[0], "webdavs") {
+		u.Scheme = "https"
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/"
+	u.RawPath = ""
+	return &WebDAV{
+		URL:      u,
+		Username: opts.WebDAVUsername,
+		Password: opts.WebDAVPassword,
+		HTTP:     &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// mtimeHeader keeps the modification time given to Write, since S3 sets Last-Modified itself
+const mtimeHeader = "X-Amz-Meta-Mtime"
+
+// S3 is a SaveStore backed by the objects directly under a prefix of an S3 or S3-compatible
+// bucket, such as MinIO. Objects are written in one piece, so a listed object is always complete.
+type S3 struct {
+	// Endpoint is the service URL for path-style requests, e.g. http://127.0.0.1:9000 for a
+	// local MinIO; empty to use AWS with virtual-hosted buckets
+	Endpoint  string
+	Region    string
+	Bucket    string
+	Prefix    string // key prefix ending in "/", or empty for the bucket root
+	AccessKey string
+	SecretKey string
+	HTTP      *http.Client
+}
+
+// objectURL returns the URL of key, or of the bucket when key is empty
+func (s *S3) objectURL(key string) *url.URL {
+	var u *url.URL
+	if s.Endpoint != "" {
+		u, _ = url.Parse(strings.TrimSuffix(s.Endpoint, "/"))
+		u.Path += "/" + s.Bucket + "/" + key
+	} else {
+		u = &url.URL{Scheme: "https", Host: s.Bucket + ".s3." + s.Region + ".amazonaws.com", Path: "/" + key}
+	}
+	u.RawPath = uriEncode(u.Path, false)
+	return u
+}
+
+// do sends a signed request and returns the response if its status is 2xx. A body of
+// size bytes is streamed with an unsigned payload rather than hashed up front.
+// A 404 is reported as fs.ErrNotExist for name.
+func (s *S3) do(method string, u *url.URL, body io.Reader, size int64, header http.Header, name string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.URL = u
+	payloadHash := emptyPayloadHash
+	if body != nil && size > 0 {
+		req.Body, req.ContentLength = io.NopCloser(body), size
+		payloadHash = unsignedPayload
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	signV4(req, payloadHash, s.Region, s.AccessKey, s.SecretKey, time.Now())
+
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3 %s %s: %w", method, name, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &fs.PathError{Op: strings.ToLower(method), Path: name, Err: fs.ErrNotExist}
+	}
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return nil, fmt.Errorf("s3 %s %s: status %d: %s", method, name, resp.StatusCode, s3ErrorMessage(msg))
+}
+
+// s3ErrorMessage extracts the code and message from an S3 error document
+func s3ErrorMessage(body []byte) string {
+	var e struct {
+		Code    string `xml:"Code"`
+		Message string `xml:"Message"`
+	}
+	if xml.Unmarshal(body, &e) != nil || e.Code == "" {
+		return strings.TrimSpace(string(body))
+	}
+	return e.Code + ": " + e.Message
+}
+
+// List returns the names of the objects directly under the prefix.
+func (s *S3) List() ([]string, error) {
+	var names []string
+	token := ""
+	for {
+		u := s.objectURL("")
+		q := url.Values{"list-type": {"2"}, "delimiter": {"/"}}
+		if s.Prefix != "" {
+			q.Set("prefix", s.Prefix)
+		}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		u.RawQuery = q.Encode()
+
+		resp, err := s.do(http.MethodGet, u, nil, 0, nil, s.Prefix)
+		if err != nil {
+			return nil, err
+		}
+		var page struct {
+			Contents []struct {
+				Key string `xml:"Key"`
+			} `xml:"Contents"`
+			IsTruncated           bool   `xml:"IsTruncated"`
+			NextContinuationToken string `xml:"NextContinuationToken"`
+		}
+		err = xml.NewDecoder(resp.Body).Decode(&page)
+		resp.Body.Close()
+		if err != nil {
+			return nil, fmt.Errorf("decoding S3 listing: %w", err)
+		}
+		for _, obj := range page.Contents {
+			name := strings.TrimPrefix(obj.Key, s.Prefix)
+			// Skip the folder marker some tools create for the prefix itself
+			if name != "" && !strings.Contains(name, "/") {
+				names = append(names, name)
+			}
+		}
+		if !page.IsTruncated || page.NextContinuationToken == "" {
+			return names, nil
+		}
+		token = page.NextContinuationToken
+	}
+}
+
+// Stat describes an object. The modification time is the one given to Write, if any,
+// and otherwise the object's Last-Modified time.
+func (s *S3) Stat(name string) (FileInfo, error) {
+	resp, err := s.do(http.MethodHead, s.objectURL(s.Prefix+name), nil, 0, nil, name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	resp.Body.Close()
+
+	fi := FileInfo{Name: name, Size: resp.ContentLength, ETag: strings.Trim(resp.Header.Get("ETag"), `"`)}
+	if sec, err := strconv.ParseInt(resp.Header.Get(mtimeHeader), 10, 64); err == nil {
+		fi.ModTime = time.Unix(sec, 0)
+	} else if t, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		fi.ModTime = t
+	}
+	return fi, nil
+}
+
+// Open downloads an object, streaming its contents.
+func (s *S3) Open(name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, s.objectURL(s.Prefix+name), nil, 0, nil, name)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Rename copies an object to its new key and deletes the old one, as S3 has no rename.
+func (s *S3) Rename(oldName, newName string) error {
+	header := http.Header{}
+	header.Set("X-Amz-Copy-Source", uriEncode("/"+s.Bucket+"/"+s.Prefix+oldName, false))
+	resp, err := s.do(http.MethodPut, s.objectURL(s.Prefix+newName), nil, 0, header, newName)
+	if err != nil {
+		return err
+	}
+	// A copy can fail after the 200 status line; the body then holds an error document
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if bytes.Contains(body, []byte("<Error>")) {
+		return fmt.Errorf("s3 copy %s to %s: %s", oldName, newName, s3ErrorMessage(body))
+	}
+
+	resp, err = s.do(http.MethodDelete, s.objectURL(s.Prefix+oldName), nil, 0, nil, oldName)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// Write uploads r as a single object, recording modTime in its metadata.
+func (s *S3) Write(name string, r io.Reader, size int64, modTime time.Time) error {
+	// S3 needs the length up front, so only readers of unknown size are buffered
+	if size < 0 {
+		body, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		r, size = bytes.NewReader(body), int64(len(body))
+	}
+	header := http.Header{}
+	if !modTime.IsZero() {
+		header.Set(mtimeHeader, strconv.FormatInt(modTime.Unix(), 10))
+	}
+	resp, err := s.do(http.MethodPut, s.objectURL(s.Prefix+name), r, size, header, name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
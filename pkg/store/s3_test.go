@@ -0,0 +1,355 @@
+package store
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	fakeAccessKey = "test-access"
+	fakeSecretKey = "test-secret"
+	fakeBucket    = "saves"
+)
+
+type fakeObject struct {
+	data  []byte
+	mtime string // X-Amz-Meta-Mtime
+}
+
+// fakeS3 is a path-style S3 stand-in that checks every request's SigV4 signature and
+// payload hash, lists at most pageSize keys per page and logs the requests it served.
+type fakeS3 struct {
+	mu       sync.Mutex
+	objects  map[string]fakeObject
+	pageSize int
+	requests []string // "METHOD key"
+	unsigned int      // uploads sent with an unsigned payload
+}
+
+func newFakeS3(t *testing.T) (*fakeS3, *S3) {
+	t.Helper()
+	f := &fakeS3{objects: make(map[string]fakeObject), pageSize: 2}
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	return f, &S3{
+		Endpoint:  srv.URL,
+		Region:    "eu-west-1",
+		Bucket:    fakeBucket,
+		Prefix:    "game/",
+		AccessKey: fakeAccessKey,
+		SecretKey: fakeSecretKey,
+		HTTP:      srv.Client(),
+	}
+}
+
+func (f *fakeS3) put(key, data string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.objects[key] = fakeObject{data: []byte(data)}
+}
+
+func (f *fakeS3) fail(w http.ResponseWriter, status int, code string) {
+	w.WriteHeader(status)
+	fmt.Fprintf(w, "<Error><Code>%s</Code><Message>%s</Message></Error>", code, code)
+}
+
+// verify recomputes the signature of the request as it arrived, returning an S3 error code
+// if it doesn't match
+func (f *fakeS3) verify(r *http.Request, body []byte) string {
+	sum := sha256.Sum256(body)
+	if got := r.Header.Get("X-Amz-Content-Sha256"); got != hex.EncodeToString(sum[:]) && got != unsignedPayload {
+		return "XAmzContentSHA256Mismatch"
+	}
+	signed, err := time.Parse("20060102T150405Z", r.Header.Get("X-Amz-Date"))
+	if err != nil {
+		return "MissingDate"
+	}
+	check := r.Clone(r.Context())
+	check.URL.Host = r.Host
+	// S3 signs the decoded path encoded its own way, whatever encoding the client sent
+	check.URL.RawPath = uriEncode(r.URL.Path, false)
+	check.Header.Del("Authorization")
+	signV4(check, r.Header.Get("X-Amz-Content-Sha256"), "eu-west-1", fakeAccessKey, fakeSecretKey, signed)
+	if check.Header.Get("Authorization") != r.Header.Get("Authorization") {
+		return "SignatureDoesNotMatch"
+	}
+	return ""
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	if code := f.verify(r, body); code != "" {
+		f.fail(w, http.StatusForbidden, code)
+		return
+	}
+	bucket, key, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	if bucket != fakeBucket {
+		f.fail(w, http.StatusNotFound, "NoSuchBucket")
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+key)
+
+	if key == "" && r.Method == http.MethodGet {
+		f.list(w, r.URL.Query())
+		return
+	}
+	obj, exists := f.objects[key]
+	switch r.Method {
+	case http.MethodHead, http.MethodGet:
+		if !exists {
+			f.fail(w, http.StatusNotFound, "NoSuchKey")
+			return
+		}
+		sum := md5.Sum(obj.data)
+		w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:])+`"`)
+		w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+		w.Header().Set("Last-Modified", time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC).Format(http.TimeFormat))
+		if obj.mtime != "" {
+			w.Header().Set(mtimeHeader, obj.mtime)
+		}
+		if r.Method == http.MethodGet {
+			w.Write(obj.data)
+		}
+	case http.MethodPut:
+		if src := r.Header.Get("X-Amz-Copy-Source"); src != "" {
+			path, err := url.PathUnescape(src)
+			from, ok := strings.CutPrefix(path, "/"+fakeBucket+"/")
+			source, found := f.objects[from]
+			if err != nil || !ok || !found {
+				// S3 reports some copy failures in the body of a 200 response
+				w.Write([]byte("<Error><Code>NoSuchKey</Code><Message>The specified key does not exist.</Message></Error>"))
+				return
+			}
+			f.objects[key] = source
+			w.Write([]byte("<CopyObjectResult><ETag>\"x\"</ETag></CopyObjectResult>"))
+			return
+		}
+		// S3 does not take chunked uploads without aws-chunked signing
+		if r.ContentLength < 0 {
+			f.fail(w, http.StatusLengthRequired, "MissingContentLength")
+			return
+		}
+		if r.Header.Get("X-Amz-Content-Sha256") == unsignedPayload {
+			f.unsigned++
+		}
+		f.objects[key] = fakeObject{data: body, mtime: r.Header.Get(mtimeHeader)}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		f.fail(w, http.StatusMethodNotAllowed, "MethodNotAllowed")
+	}
+}
+
+// list answers a ListObjectsV2 request, paging with the key to continue after as the token
+func (f *fakeS3) list(w http.ResponseWriter, q url.Values) {
+	if q.Get("list-type") != "2" || q.Get("delimiter") != "/" {
+		f.fail(w, http.StatusBadRequest, "InvalidArgument")
+		return
+	}
+	prefix := q.Get("prefix")
+	var keys []string
+	prefixes := make(map[string]bool)
+	for k := range f.objects {
+		rest, ok := strings.CutPrefix(k, prefix)
+		if !ok {
+			continue
+		}
+		if i := strings.Index(rest, "/"); i >= 0 {
+			prefixes[prefix+rest[:i+1]] = true
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	after := q.Get("continuation-token")
+	start := sort.SearchStrings(keys, after)
+	if start < len(keys) && keys[start] == after {
+		start++
+	}
+
+	type content struct {
+		Key string `xml:"Key"`
+	}
+	type commonPrefix struct {
+		Prefix string `xml:"Prefix"`
+	}
+	result := struct {
+		XMLName               xml.Name       `xml:"ListBucketResult"`
+		Contents              []content      `xml:"Contents"`
+		CommonPrefixes        []commonPrefix `xml:"CommonPrefixes"`
+		IsTruncated           bool           `xml:"IsTruncated"`
+		NextContinuationToken string         `xml:"NextContinuationToken,omitempty"`
+	}{}
+	end := min(start+f.pageSize, len(keys))
+	for _, k := range keys[start:end] {
+		result.Contents = append(result.Contents, content{Key: k})
+	}
+	if after == "" {
+		for p := range prefixes {
+			result.CommonPrefixes = append(result.CommonPrefixes, commonPrefix{Prefix: p})
+		}
+	}
+	if end < len(keys) {
+		result.IsTruncated = true
+		result.NextContinuationToken = keys[end-1]
+	}
+	xml.NewEncoder(w).Encode(result)
+}
+
+func TestS3List(t *testing.T) {
+	f, s := newFakeS3(t)
+	for _, k := range []string{
+		"game/", // folder marker
+		"game/pbem1_turn1_Alice.se1",
+		"game/pbem1_turn1_Bob.se1",
+		"game/pbem1 turn2 Jörg+1.se1",
+		"game/resign_carol",
+		"game/old/pbem1_turn0_Alice.se1",
+		"other/pbem2_turn1_Alice.se1",
+	} {
+		f.put(k, "x")
+	}
+
+	names, err := s.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(names)
+	want := []string{"pbem1 turn2 Jörg+1.se1", "pbem1_turn1_Alice.se1", "pbem1_turn1_Bob.se1", "resign_carol"}
+	if !slices.Equal(names, want) {
+		t.Errorf("List = %q, want %q", names, want)
+	}
+
+	// Five keys under the prefix take three pages of two
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if len(f.requests) != 3 {
+		t.Errorf("List made %d requests, want 3: %q", len(f.requests), f.requests)
+	}
+}
+
+func TestS3ReadWrite(t *testing.T) {
+	f, s := newFakeS3(t)
+	modTime := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	name := "pbem1 turn2 Jörg+1.se1"
+
+	if err := s.Write(name, strings.NewReader("save data"), 9, modTime); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	fi, err := s.Stat(name)
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	sum := md5.Sum([]byte("save data"))
+	if fi.Size != 9 || !fi.ModTime.Equal(modTime) || fi.ETag != hex.EncodeToString(sum[:]) {
+		t.Errorf("Stat = %+v, want 9 bytes from %s with an unquoted ETag", fi, modTime)
+	}
+	r, err := s.Open(name)
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "save data" {
+		t.Errorf("Open read %q", data)
+	}
+
+	// Objects written by other tools fall back to Last-Modified
+	f.put("game/uploaded.se1", "x")
+	if fi, err := s.Stat("uploaded.se1"); err != nil || fi.ModTime.Year() != 2026 || fi.ModTime.Month() != time.January {
+		t.Errorf("Stat of an object without metadata = %+v, %v", fi, err)
+	}
+
+	// Copies stream one response body into the upload; readers of unknown size are buffered
+	src, err := s.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	if err := s.Write("copy.se1", src, fi.Size, modTime); err != nil {
+		t.Fatalf("Write of a copy: %v", err)
+	}
+	if err := s.Write("unknown size.se1", io.MultiReader(strings.NewReader("save "), strings.NewReader("data")), -1, time.Time{}); err != nil {
+		t.Fatalf("Write of unknown size: %v", err)
+	}
+	if err := s.Write("empty.se1", strings.NewReader(""), 0, time.Time{}); err != nil {
+		t.Fatalf("Write of an empty object: %v", err)
+	}
+	f.mu.Lock()
+	copied, unsigned := string(f.objects["game/copy.se1"].data), f.unsigned
+	buffered := string(f.objects["game/unknown size.se1"].data)
+	f.mu.Unlock()
+	if copied != "save data" || buffered != "save data" {
+		t.Errorf("uploaded %q and %q, want %q", copied, buffered, "save data")
+	}
+	if unsigned != 3 {
+		t.Errorf("%d uploads had an unsigned payload, want 3", unsigned)
+	}
+
+	if _, err := s.Stat("missing.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of a missing object: err = %v, want fs.ErrNotExist", err)
+	}
+	if _, err := s.Open("missing.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open of a missing object: err = %v, want fs.ErrNotExist", err)
+	}
+
+	wrong := *s
+	wrong.SecretKey = "wrong"
+	if _, err := wrong.Open(name); err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Open with a wrong key: err = %v, want a signature error", err)
+	}
+}
+
+func TestS3Rename(t *testing.T) {
+	f, s := newFakeS3(t)
+	f.put("game/mygame_turn1_Bob.se1", "save")
+
+	if err := s.Rename("mygame_turn1_Bob.se1", "pbem1 turn1 Bob.se1"); err != nil {
+		t.Fatalf("Rename: %v", err)
+	}
+	f.mu.Lock()
+	wantRequests := []string{"PUT game/pbem1 turn1 Bob.se1", "DELETE game/mygame_turn1_Bob.se1"}
+	if !slices.Equal(f.requests, wantRequests) {
+		t.Errorf("Rename sent %q, want a copy then a delete: %q", f.requests, wantRequests)
+	}
+	if obj, ok := f.objects["game/pbem1 turn1 Bob.se1"]; !ok || !bytes.Equal(obj.data, []byte("save")) {
+		t.Errorf("renamed object = %+v, %v", obj, ok)
+	}
+	if _, ok := f.objects["game/mygame_turn1_Bob.se1"]; ok {
+		t.Error("the old object is still there")
+	}
+	f.requests = nil
+	f.mu.Unlock()
+
+	// A failed copy keeps the source
+	err := s.Rename("missing.se1", "pbem1_turn2_Alice.se1")
+	if err == nil || !strings.Contains(err.Error(), "NoSuchKey") {
+		t.Errorf("Rename of a missing object: err = %v, want the copy error", err)
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if slices.ContainsFunc(f.requests, func(r string) bool { return strings.HasPrefix(r, "DELETE") }) {
+		t.Errorf("a failed copy was followed by a delete: %q", f.requests)
+	}
+}
@@ -0,0 +1,32 @@
+package store
+
+import (
+	"io"
+	"time"
+)
+
+// FileInfo describes a file in a save store.
+type FileInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+	// ETag is a change token from backends that provide one; empty for local files
+	ETag string
+}
+
+// SaveStore is where a game's saves live. Names are plain file names without directories.
+// Errors for missing files match fs.ErrNotExist.
+type SaveStore interface {
+	// List returns the names of the files directly in the store
+	List() ([]string, error)
+	// Stat describes a single file
+	Stat(name string) (FileInfo, error)
+	// Open reads a file's contents
+	Open(name string) (io.ReadCloser, error)
+	// Rename moves a file to a new name, replacing any file already there
+	Rename(oldName, newName string) error
+	// Write creates or replaces a file with the contents of r, which holds size bytes
+	// (-1 if unknown). The modification time is set to modTime where the store supports
+	// it and modTime is not zero.
+	Write(name string, r io.Reader, size int64, modTime time.Time) error
+}
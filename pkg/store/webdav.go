@@ -0,0 +1,253 @@
+package store
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// listingTTL is how long Stat answers from the last listing, so a scan costs one PROPFIND
+const listingTTL = 2 * time.Second
+
+// propfindBody asks for the properties the monitor needs
+const propfindBody = `<?xml version="1.0" encoding="utf-8"?>
+<d:propfind xmlns:d="DAV:"><d:prop><d:resourcetype/><d:getcontentlength/><d:getlastmodified/><d:getetag/></d:prop></d:propfind>`
+
+// WebDAV is a SaveStore backed by a folder on a WebDAV server, such as a Nextcloud share.
+// Files are only downloaded when their contents are read; listing and Stat use PROPFIND.
+type WebDAV struct {
+	// URL is the folder's address, e.g. https://cloud.example.com/remote.php/dav/files/alice/Saves/
+	URL      *url.URL
+	Username string
+	Password string
+	HTTP     *http.Client
+
+	mu       sync.Mutex
+	listed   map[string]FileInfo
+	listedAt time.Time
+}
+
+// fileURL returns the address of name inside the folder
+func (w *WebDAV) fileURL(name string) *url.URL {
+	u := *w.URL
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + name
+	u.RawPath = ""
+	return &u
+}
+
+// do sends an authenticated request and returns the response if its status is 2xx.
+// body holds size bytes, or -1 if unknown. A 404 is reported as fs.ErrNotExist for name.
+func (w *WebDAV) do(method string, u *url.URL, body io.Reader, size int64, header http.Header, name string) (*http.Response, error) {
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	// Send bodies of known size with a Content-Length; PHP servers such as Nextcloud
+	// store an empty file for some chunked uploads
+	switch {
+	case body == nil || size < 0:
+	case size == 0:
+		req.Body, req.ContentLength = http.NoBody, 0
+	default:
+		req.ContentLength = size
+	}
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if w.Username != "" {
+		req.SetBasicAuth(w.Username, w.Password)
+	}
+
+	resp, err := w.HTTP.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav %s %s: %w", method, name, err)
+	}
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, &fs.PathError{Op: strings.ToLower(method), Path: name, Err: fs.ErrNotExist}
+	}
+	return nil, fmt.Errorf("webdav %s %s: %s", method, name, resp.Status)
+}
+
+// propfind describes the files at u, or the files directly inside it with depth 1
+func (w *WebDAV) propfind(u *url.URL, depth, name string) ([]FileInfo, error) {
+	header := http.Header{}
+	header.Set("Depth", depth)
+	header.Set("Content-Type", "application/xml; charset=utf-8")
+	resp, err := w.do("PROPFIND", u, strings.NewReader(propfindBody), int64(len(propfindBody)), header, name)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var ms struct {
+		Responses []struct {
+			Href     string `xml:"DAV: href"`
+			Propstat []struct {
+				Status string `xml:"DAV: status"`
+				Prop   struct {
+					ResourceType struct {
+						Collection *struct{} `xml:"DAV: collection"`
+					} `xml:"DAV: resourcetype"`
+					ContentLength string `xml:"DAV: getcontentlength"`
+					LastModified  string `xml:"DAV: getlastmodified"`
+					ETag          string `xml:"DAV: getetag"`
+				} `xml:"DAV: prop"`
+			} `xml:"DAV: propstat"`
+		} `xml:"DAV: response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("decoding WebDAV listing: %w", err)
+	}
+
+	folder := norm.NFC.String(path.Clean("/" + w.URL.Path))
+	var files []FileInfo
+	for _, r := range ms.Responses {
+		// Only files directly in the folder; the folder itself and subfolders are skipped
+		p := strings.TrimSuffix(hrefPath(u, r.Href), "/")
+		if p == "" || norm.NFC.String(path.Dir(p)) != folder {
+			continue
+		}
+		fi := FileInfo{Name: path.Base(p)}
+		collection := false
+		for _, ps := range r.Propstat {
+			// Properties the server doesn't have come back in a separate 404 propstat
+			if !strings.Contains(ps.Status, " 200 ") {
+				continue
+			}
+			collection = collection || ps.Prop.ResourceType.Collection != nil
+			if n, err := strconv.ParseInt(strings.TrimSpace(ps.Prop.ContentLength), 10, 64); err == nil {
+				fi.Size = n
+			}
+			if t, err := http.ParseTime(strings.TrimSpace(ps.Prop.LastModified)); err == nil {
+				fi.ModTime = t
+			}
+			if ps.Prop.ETag != "" {
+				fi.ETag = strings.Trim(strings.TrimPrefix(strings.TrimSpace(ps.Prop.ETag), "W/"), `"`)
+			}
+		}
+		if !collection {
+			files = append(files, fi)
+		}
+	}
+	return files, nil
+}
+
+// hrefPath returns the decoded path of a multistatus href, which may be a full URL, an absolute
+// path or relative to the request URL. Hrefs that are not valid URLs are taken literally.
+func hrefPath(base *url.URL, href string) string {
+	href = strings.TrimSpace(href)
+	if u, err := url.Parse(href); err == nil {
+		return base.ResolveReference(u).Path
+	}
+	if _, rest, ok := strings.Cut(href, "://"); ok {
+		href = "/"
+		if i := strings.IndexByte(rest, '/'); i >= 0 {
+			href = rest[i:]
+		}
+	}
+	return base.ResolveReference(&url.URL{Path: href}).Path
+}
+
+// List returns the names of the files in the folder and remembers their details for Stat.
+func (w *WebDAV) List() ([]string, error) {
+	files, err := w.propfind(w.URL, "1", w.URL.Path)
+	if err != nil {
+		return nil, err
+	}
+	listed := make(map[string]FileInfo, len(files))
+	names := make([]string, 0, len(files))
+	for _, fi := range files {
+		listed[fi.Name] = fi
+		names = append(names, fi.Name)
+	}
+
+	w.mu.Lock()
+	w.listed, w.listedAt = listed, time.Now()
+	w.mu.Unlock()
+	return names, nil
+}
+
+// Stat describes a file, from the last listing if it is recent and otherwise with its own PROPFIND.
+func (w *WebDAV) Stat(name string) (FileInfo, error) {
+	w.mu.Lock()
+	fi, ok := w.listed[name]
+	fresh := time.Since(w.listedAt) < listingTTL
+	w.mu.Unlock()
+	if ok && fresh {
+		return fi, nil
+	}
+
+	files, err := w.propfind(w.fileURL(name), "0", name)
+	if err != nil {
+		return FileInfo{}, err
+	}
+	for _, fi := range files {
+		if norm.NFC.String(fi.Name) == norm.NFC.String(name) {
+			fi.Name = name
+			return fi, nil
+		}
+	}
+	return FileInfo{}, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// Open downloads a file, streaming its contents.
+func (w *WebDAV) Open(name string) (io.ReadCloser, error) {
+	resp, err := w.do(http.MethodGet, w.fileURL(name), nil, 0, nil, name)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Rename moves a file on the server, replacing any file already there.
+func (w *WebDAV) Rename(oldName, newName string) error {
+	header := http.Header{}
+	header.Set("Destination", w.fileURL(newName).String())
+	header.Set("Overwrite", "T")
+	resp, err := w.do("MOVE", w.fileURL(oldName), nil, 0, header, oldName)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	w.forget(oldName, newName)
+	return nil
+}
+
+// Write streams r to a file. The modification time is sent in the X-OC-Mtime header, which
+// Nextcloud and ownCloud apply; other servers use the upload time.
+func (w *WebDAV) Write(name string, r io.Reader, size int64, modTime time.Time) error {
+	header := http.Header{}
+	if !modTime.IsZero() {
+		header.Set("X-OC-Mtime", strconv.FormatInt(modTime.Unix(), 10))
+	}
+	resp, err := w.do(http.MethodPut, w.fileURL(name), r, size, header, name)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	w.forget(name)
+	return nil
+}
+
+// forget drops files changed by the bot from the last listing
+func (w *WebDAV) forget(names ...string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for _, name := range names {
+		delete(w.listed, name)
+	}
+}
@@ -0,0 +1,321 @@
+package store
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/webdav"
+)
+
+// davLog records the requests a WebDAV stand-in served
+type davLog struct {
+	mu       sync.Mutex
+	requests []string         // "METHOD path"
+	lengths  map[string]int64 // Content-Length of each PUT, -1 when chunked
+}
+
+func (l *davLog) wrap(t *testing.T, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "alice" || pass != "app-password" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		l.mu.Lock()
+		l.requests = append(l.requests, r.Method+" "+r.URL.Path)
+		if r.Method == http.MethodPut {
+			l.lengths[r.URL.Path] = r.ContentLength
+		}
+		l.mu.Unlock()
+		h.ServeHTTP(w, r)
+	})
+}
+
+func (l *davLog) count(method string) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	n := 0
+	for _, r := range l.requests {
+		if strings.HasPrefix(r, method+" ") {
+			n++
+		}
+	}
+	return n
+}
+
+// newDAVServer serves an in-memory WebDAV tree with a folder named like a Nextcloud share
+// and returns a store for that folder
+func newDAVServer(t *testing.T) (*WebDAV, *davLog) {
+	t.Helper()
+	const folder = "/remote.php/dav/files/alice/My Saves Jörg/"
+	log := &davLog{lengths: make(map[string]int64)}
+	handler := &webdav.Handler{
+		Prefix:     "/remote.php/dav/files/alice",
+		FileSystem: webdav.NewMemFS(),
+		LockSystem: webdav.NewMemLS(),
+	}
+	srv := httptest.NewServer(log.wrap(t, handler))
+	t.Cleanup(srv.Close)
+
+	st, err := Open("webdav"+strings.TrimPrefix(srv.URL, "http")+strings.ReplaceAll(url.PathEscape(folder), "%2F", "/"),
+		Options{WebDAVUsername: "alice", WebDAVPassword: "app-password"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := st.(*WebDAV)
+	for _, dir := range []string{"/My Saves Jörg", "/My Saves Jörg/old"} {
+		if err := handler.FileSystem.Mkdir(t.Context(), dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return w, log
+}
+
+func TestWebDAVStore(t *testing.T) {
+	w, log := newDAVServer(t)
+
+	// Readers of unknown length are streamed; others are sent with their length
+	if err := w.Write("pbem1 turn1 Bob.se1", io.MultiReader(strings.NewReader("bob's "), strings.NewReader("save")), -1, time.Time{}); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	local := filepath.Join(t.TempDir(), "save.se1")
+	if err := os.WriteFile(local, []byte("alice's save"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(local)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := w.Write("pbem1_turn2_Älice.se1", f, 12, time.Time{}); err != nil {
+		t.Fatalf("Write from a file: %v", err)
+	}
+	if err := w.Write("old/pbem1_turn0_Bob.se1", strings.NewReader("old"), 3, time.Time{}); err != nil {
+		t.Fatalf("Write into a subfolder: %v", err)
+	}
+	log.mu.Lock()
+	lengths := []int64{log.lengths["/remote.php/dav/files/alice/My Saves Jörg/pbem1 turn1 Bob.se1"], log.lengths["/remote.php/dav/files/alice/My Saves Jörg/pbem1_turn2_Älice.se1"]}
+	log.mu.Unlock()
+	if !slices.Equal(lengths, []int64{-1, 12}) {
+		t.Errorf("PUT content lengths = %v, want chunked then 12", lengths)
+	}
+
+	// The folder itself and the subfolder are not listed
+	names, err := w.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(names)
+	if want := []string{"pbem1 turn1 Bob.se1", "pbem1_turn2_Älice.se1"}; !slices.Equal(names, want) {
+		t.Errorf("List = %q, want %q", names, want)
+	}
+
+	// Stat answers from the listing until it is older than listingTTL
+	propfinds := log.count("PROPFIND")
+	fi, err := w.Stat("pbem1 turn1 Bob.se1")
+	if err != nil {
+		t.Fatalf("Stat: %v", err)
+	}
+	if fi.Size != 10 || fi.ModTime.IsZero() || fi.ETag == "" || strings.ContainsAny(fi.ETag, `"/`) {
+		t.Errorf("Stat = %+v, want 10 bytes, a modification time and an unquoted ETag", fi)
+	}
+	if n := log.count("PROPFIND"); n != propfinds {
+		t.Errorf("Stat after List sent %d PROPFIND(s), want none", n-propfinds)
+	}
+	w.mu.Lock()
+	w.listedAt = time.Now().Add(-listingTTL)
+	w.mu.Unlock()
+	fresh, err := w.Stat("pbem1 turn1 Bob.se1")
+	if err != nil || fresh != fi {
+		t.Errorf("Stat with its own PROPFIND = %+v, %v; want %+v", fresh, err, fi)
+	}
+	if n := log.count("PROPFIND"); n != propfinds+1 {
+		t.Errorf("Stat after the listing expired sent %d PROPFIND(s), want 1", n-propfinds)
+	}
+	if _, err := w.Stat("missing.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of a missing file: err = %v, want fs.ErrNotExist", err)
+	}
+
+	r, err := w.Open("pbem1_turn2_Älice.se1")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "alice's save" {
+		t.Errorf("Open read %q", data)
+	}
+	if _, err := w.Open("missing.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Open of a missing file: err = %v, want fs.ErrNotExist", err)
+	}
+}
+
+func TestWebDAVCopySendsLength(t *testing.T) {
+	w, log := newDAVServer(t)
+	if err := w.Write("game_turn3_alice.se1", strings.NewReader("save data"), -1, time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write("empty.se1", strings.NewReader(""), 0, time.Time{}); err != nil {
+		t.Fatalf("Write of an empty file: %v", err)
+	}
+
+	// A copy streams the response body of one request into another, as auto-fix does
+	fi, err := w.Stat("game_turn3_alice.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r, err := w.Open("game_turn3_alice.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	if err := w.Write("pbem1_turn3_Alice.se1", r, fi.Size, fi.ModTime); err != nil {
+		t.Fatalf("Write of a copy: %v", err)
+	}
+
+	const folder = "/remote.php/dav/files/alice/My Saves Jörg/"
+	log.mu.Lock()
+	lengths := []int64{log.lengths[folder+"pbem1_turn3_Alice.se1"], log.lengths[folder+"empty.se1"]}
+	log.mu.Unlock()
+	if !slices.Equal(lengths, []int64{9, 0}) {
+		t.Errorf("PUT content lengths = %v, want 9 for the copy and 0 for the empty file", lengths)
+	}
+	copied, err := w.Open("pbem1_turn3_Alice.se1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(copied)
+	copied.Close()
+	if string(data) != "save data" {
+		t.Errorf("copy holds %q, want %q", data, "save data")
+	}
+}
+
+func TestWebDAVRenameOverwrites(t *testing.T) {
+	w, _ := newDAVServer(t)
+	for name, data := range map[string]string{"mygame turn1 Bob.se1": "new", "pbem1 turn1 Bob.se1": "stale"} {
+		if err := w.Write(name, strings.NewReader(data), int64(len(data)), time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := w.List(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := w.Rename("mygame turn1 Bob.se1", "pbem1 turn1 Bob.se1"); err != nil {
+		t.Fatalf("Rename onto an existing file: %v", err)
+	}
+	// The renamed files are dropped from the cached listing
+	if _, err := w.Stat("mygame turn1 Bob.se1"); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Stat of the old name: err = %v, want fs.ErrNotExist", err)
+	}
+	r, err := w.Open("pbem1 turn1 Bob.se1")
+	if err != nil {
+		t.Fatalf("Open: %v", err)
+	}
+	data, _ := io.ReadAll(r)
+	r.Close()
+	if string(data) != "new" {
+		t.Errorf("renamed file holds %q, want the moved contents", data)
+	}
+}
+
+// multistatus is a listing as servers other than x/net/webdav write it: hrefs as full URLs,
+// relative or unescaped, names in decomposed Unicode, weak ETags and properties the server
+// doesn't have reported in a 404 propstat after the found ones
+const multistatus = `<?xml version="1.0" encoding="utf-8"?>
+<d:multistatus xmlns:d="DAV:">
+ <d:response><d:href>/dav/Saves%20e%CC%81te%CC%81/</d:href>
+  <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>{{base}}/dav/Saves%20e%CC%81te%CC%81/pbem1_turn1_Bob.se1</d:href>
+  <d:propstat><d:prop><d:resourcetype/><d:getcontentlength>42</d:getcontentlength><d:getlastmodified>Sun, 01 Mar 2026 12:00:00 GMT</d:getlastmodified></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  <d:propstat><d:prop><d:getetag/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>pbem1_turn2_Alice.se1</d:href>
+  <d:propstat><d:prop><d:getetag>W/"abc123"</d:getetag><d:getcontentlength>7</d:getcontentlength></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+  <d:propstat><d:prop><d:getcontentlength/><d:getlastmodified/></d:prop><d:status>HTTP/1.1 404 Not Found</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>/dav/Saves été/100% done.se1</d:href>
+  <d:propstat><d:prop><d:getetag>"x"</d:getetag></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>/dav/Saves%20%C3%A9t%C3%A9/old/</d:href>
+  <d:propstat><d:prop><d:resourcetype><d:collection/></d:resourcetype></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>/dav/Saves%20%C3%A9t%C3%A9/old/pbem1_turn0_Bob.se1</d:href>
+  <d:propstat><d:prop><d:getcontentlength>1</d:getcontentlength></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+ <d:response><d:href>/dav/Other/pbem2_turn1_Bob.se1</d:href>
+  <d:propstat><d:prop><d:getcontentlength>1</d:getcontentlength></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat>
+ </d:response>
+</d:multistatus>`
+
+func TestWebDAVMultistatus(t *testing.T) {
+	var base string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "PROPFIND" || r.Header.Get("Depth") != "1" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+		w.WriteHeader(http.StatusMultiStatus)
+		io.WriteString(w, strings.ReplaceAll(multistatus, "{{base}}", base))
+	}))
+	defer srv.Close()
+	base = srv.URL
+
+	// The folder is configured in composed Unicode
+	st, err := Open("webdav"+strings.TrimPrefix(srv.URL, "http")+"/dav/Saves%20%C3%A9t%C3%A9", Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := st.(*WebDAV)
+	names, err := w.List()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	sort.Strings(names)
+	if want := []string{"100% done.se1", "pbem1_turn1_Bob.se1", "pbem1_turn2_Alice.se1"}; !slices.Equal(names, want) {
+		t.Fatalf("List = %q, want %q", names, want)
+	}
+
+	want := map[string]FileInfo{
+		"pbem1_turn1_Bob.se1":   {Name: "pbem1_turn1_Bob.se1", Size: 42, ModTime: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		"pbem1_turn2_Alice.se1": {Name: "pbem1_turn2_Alice.se1", Size: 7, ETag: "abc123"},
+		"100% done.se1":         {Name: "100% done.se1", ETag: "x"},
+	}
+	for name, fi := range want {
+		got, err := w.Stat(name)
+		if err != nil || got != fi {
+			t.Errorf("Stat(%q) = %+v, %v; want %+v", name, got, err, fi)
+		}
+	}
+}
+
+func TestHrefPath(t *testing.T) {
+	base, _ := url.Parse("https://cloud.example.com/dav/My%20Saves/")
+	tests := map[string]string{
+		"/dav/My%20Saves/a%20b.se1":                            "/dav/My Saves/a b.se1",
+		"https://cloud.example.com/dav/My%20Saves/a%C3%A9.se1": "/dav/My Saves/aé.se1",
+		"a.se1":                          "/dav/My Saves/a.se1",
+		" /dav/My Saves/100%.se1 ":       "/dav/My Saves/100%.se1",
+		"https://cloud.example.com/50%/": "/50%/",
+		"100%.se1":                       "/dav/My Saves/100%.se1",
+	}
+	for href, want := range tests {
+		if got := hrefPath(base, href); got != want {
+			t.Errorf("hrefPath(%q) = %q, want %q", href, got, want)
+		}
+	}
+}
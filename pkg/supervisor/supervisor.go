@@ -0,0 +1,124 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"runtime/debug"
+	"time"
+)
+
+// Crash describes a failed run of a supervised function
+type Crash struct {
+	Name    string
+	Err     error
+	Stack   []byte // set when the run panicked
+	Attempt int    // consecutive failures so far, starting at 1
+	Backoff time.Duration
+	Final   bool // the error was permanent and fn will not be restarted
+}
+
+// permanentError marks a failure that restarting cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent wraps err so that Run stops instead of restarting, for failures such as
+// configuration errors that will not go away on their own. A nil err stays nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was wrapped by Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
+
+// Options controls restart behaviour. Zero values select the defaults.
+type Options struct {
+	InitialBackoff time.Duration // default 1s
+	MaxBackoff     time.Duration // default 5m
+	StableAfter    time.Duration // a run lasting this long resets the backoff, default 10m
+	OnCrash        func(Crash)
+}
+
+// Run calls fn until ctx is canceled. When fn panics or returns an error it is restarted
+// after an exponentially growing delay; a nil return ends supervision. An error wrapped by
+// Permanent is reported once and returned without restarting.
+func Run(ctx context.Context, name string, fn func(context.Context) error, opts Options) error {
+	if opts.InitialBackoff <= 0 {
+		opts.InitialBackoff = time.Second
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = 5 * time.Minute
+	}
+	if opts.StableAfter <= 0 {
+		opts.StableAfter = 10 * time.Minute
+	}
+
+	backoff := opts.InitialBackoff
+	attempt := 0
+	for {
+		started := time.Now()
+		stack, err := runProtected(ctx, fn)
+		if ctx.Err() != nil || (err == nil && stack == nil) {
+			return nil
+		}
+
+		// A long healthy run starts the failure count over
+		if time.Since(started) >= opts.StableAfter {
+			backoff = opts.InitialBackoff
+			attempt = 0
+		}
+		attempt++
+
+		if stack == nil && IsPermanent(err) {
+			log.Printf("💥 %s failed: %v\n", name, err)
+			log.Printf("🛑 Not restarting %s; fix the problem and restart the bot\n", name)
+			if opts.OnCrash != nil {
+				opts.OnCrash(Crash{Name: name, Err: err, Attempt: attempt, Final: true})
+			}
+			return err
+		}
+		if stack != nil {
+			log.Printf("💥 %s panicked: %v\n%s", name, err, stack)
+		} else {
+			log.Printf("💥 %s failed: %v\n", name, err)
+		}
+		log.Printf("🔁 Restarting %s in %v (attempt %d)\n", name, backoff, attempt)
+
+		if opts.OnCrash != nil {
+			opts.OnCrash(Crash{Name: name, Err: err, Stack: stack, Attempt: attempt, Backoff: backoff})
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > opts.MaxBackoff {
+			backoff = opts.MaxBackoff
+		}
+	}
+}
+
+// runProtected calls fn and converts a panic into an error plus its stack trace
+func runProtected(ctx context.Context, fn func(context.Context) error) (stack []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+			stack = debug.Stack()
+		}
+	}()
+	return nil, fn(ctx)
+}
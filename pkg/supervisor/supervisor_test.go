@@ -0,0 +1,67 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRunRestartsOnError(t *testing.T) {
+	calls := 0
+	var crashes []Crash
+	err := Run(context.Background(), "test", func(context.Context) error {
+		calls++
+		switch calls {
+		case 1:
+			return errors.New("transient")
+		case 2:
+			panic("boom")
+		}
+		return nil
+	}, Options{InitialBackoff: time.Millisecond, OnCrash: func(c Crash) { crashes = append(crashes, c) }})
+
+	if err != nil {
+		t.Errorf("Run = %v, want nil after a clean return", err)
+	}
+	if calls != 3 {
+		t.Errorf("fn called %d times, want 3", calls)
+	}
+	if len(crashes) != 2 || crashes[1].Stack == nil || crashes[1].Attempt != 2 || crashes[1].Backoff != 2*time.Millisecond {
+		t.Errorf("crashes = %+v", crashes)
+	}
+}
+
+func TestRunStopsOnPermanentError(t *testing.T) {
+	cause := errors.New("bad config")
+	calls := 0
+	var crashes []Crash
+	err := Run(context.Background(), "test", func(context.Context) error {
+		calls++
+		return Permanent(cause)
+	}, Options{InitialBackoff: time.Millisecond, OnCrash: func(c Crash) { crashes = append(crashes, c) }})
+
+	if !errors.Is(err, cause) || !IsPermanent(err) {
+		t.Errorf("Run = %v, want the permanent error", err)
+	}
+	if calls != 1 {
+		t.Errorf("fn called %d times, want 1", calls)
+	}
+	if len(crashes) != 1 || !crashes[0].Final {
+		t.Errorf("crashes = %+v, want one final crash", crashes)
+	}
+	if Permanent(nil) != nil || IsPermanent(cause) {
+		t.Error("Permanent(nil) should be nil and plain errors should not be permanent")
+	}
+}
+
+func TestRunStopsWhenCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Run(ctx, "test", func(context.Context) error {
+		cancel()
+		return errors.New("interrupted")
+	}, Options{})
+	if err != nil {
+		t.Errorf("Run = %v, want nil once canceled", err)
+	}
+}
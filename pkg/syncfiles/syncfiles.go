@@ -0,0 +1,67 @@
+package syncfiles
+
+import (
+	"regexp"
+	"strings"
+)
+
+// tempPattern matches transient files a sync tool writes while a transfer is in progress
+type tempPattern struct {
+	tool string
+	re   *regexp.Regexp
+}
+
+// Transfer and lock files; they never hold a finished save
+var tempPatterns = []tempPattern{
+	{"Syncthing", regexp.MustCompile(`(?i)^\.syncthing\..+\.tmp$`)},
+	{"Syncthing", regexp.MustCompile(`(?i)^~syncthing~.+\.tmp$`)},
+	{"Nextcloud", regexp.MustCompile(`(?i)^\.~lock\..+#?$`)},
+	{"Nextcloud", regexp.MustCompile(`(?i)\.part$`)},
+	{"Nextcloud", regexp.MustCompile(`(?i)^\..+\.~[0-9a-f]+$`)},
+	{"Dropbox", regexp.MustCompile(`(?i)^\.dropbox`)},
+	{"OneDrive", regexp.MustCompile(`(?i)\.partial$`)},
+	{"OneDrive", regexp.MustCompile(`^~\$`)},
+}
+
+// conflictPattern matches a conflict copy; the groups before and after the tool's marker
+// rebuild the name of the file it competes with
+type conflictPattern struct {
+	tool string
+	re   *regexp.Regexp
+}
+
+// Conflict copies, e.g. "save.sync-conflict-20240102-150405-ABCDEF1.se1" or
+// "save (Bob's conflicted copy 2024-01-02).se1"
+var conflictPatterns = []conflictPattern{
+	{"Syncthing", regexp.MustCompile(`(?i)^(.+)\.sync-conflict-\d{8}-\d{6}-[A-Z0-9]{7}(\.[^.]*)?$`)},
+	{"Dropbox", regexp.MustCompile(`(?i)^(.+) \([^()]+'s conflicted copy[^()]*\)(\.[^.]*)?$`)},
+	{"Nextcloud", regexp.MustCompile(`(?i)^(.+) \(conflicted copy[^()]*\)(\.[^.]*)?$`)},
+	{"Nextcloud", regexp.MustCompile(`(?i)^(.+)_conflict-\d{8}-\d{6}(\.[^.]*)?$`)},
+	{"OneDrive", regexp.MustCompile(`(?i)^(.+)-(?:DESKTOP|LAPTOP)-[A-Z0-9]{7}(?:-\d+)?(\.[^.]*)?$`)},
+}
+
+// Conflict is a copy a sync tool kept because a file was changed in two places at once.
+type Conflict struct {
+	Tool     string // sync tool that created the copy
+	Original string // name of the file the copy competes with
+}
+
+// Temp reports whether name is a sync tool's in-progress transfer or lock file, and which tool wrote it.
+func Temp(name string) (string, bool) {
+	for _, p := range tempPatterns {
+		if p.re.MatchString(name) {
+			return p.tool, true
+		}
+	}
+	return "", false
+}
+
+// ParseConflict reports whether name is a sync tool's conflict copy and which file it competes with.
+func ParseConflict(name string) (Conflict, bool) {
+	for _, p := range conflictPatterns {
+		if m := p.re.FindStringSubmatch(name); m != nil {
+			return Conflict{Tool: p.tool, Original: strings.TrimSpace(m[1]) + m[2]}, true
+		}
+	}
+	return Conflict{}, false
+}
@@ -0,0 +1,63 @@
+package syncfiles
+
+import "testing"
+
+func TestTemp(t *testing.T) {
+	tests := []struct {
+		name string
+		tool string // empty if not a temporary file
+	}{
+		{".syncthing.PBEM1_turn3_Bob.se1.tmp", "Syncthing"},
+		{"~syncthing~PBEM1_turn3_Bob.se1.tmp", "Syncthing"},
+		{".SYNCTHING.PBEM1_turn3_Bob.se1.TMP", "Syncthing"},
+		{".~lock.PBEM1_turn3_Bob.se1#", "Nextcloud"},
+		{"PBEM1_turn3_Bob.se1.part", "Nextcloud"},
+		{".PBEM1_turn3_Bob.se1.~3f9a1c", "Nextcloud"},
+		{".dropbox.cache", "Dropbox"},
+		{".dropbox", "Dropbox"},
+		{"PBEM1_turn3_Bob.se1.partial", "OneDrive"},
+		{"~$PBEM1_turn3_Bob.se1", "OneDrive"},
+		{"PBEM1_turn3_Bob.se1", ""},
+		{"PBEM1_turn3_Bob.se1.tmp", ""}, // the game profile decides about its own temp files
+		{"syncthing_turn3_Bob.se1", ""},
+		{"PBEM1_turn3_Bob.sync-conflict-20240102-150405-ABCDEF1.se1", ""},
+		{"PBEM1_turn3_party.se1", ""},
+	}
+	for _, tt := range tests {
+		tool, ok := Temp(tt.name)
+		if ok != (tt.tool != "") || tool != tt.tool {
+			t.Errorf("Temp(%q) = %q, %v; want %q", tt.name, tool, ok, tt.tool)
+		}
+	}
+}
+
+func TestParseConflict(t *testing.T) {
+	tests := []struct {
+		name     string
+		tool     string // empty if not a conflict copy
+		original string
+	}{
+		{"PBEM1_turn3_Bob.sync-conflict-20240102-150405-ABCDEF1.se1", "Syncthing", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob.sync-conflict-20240102-150405-ABCDEF1", "Syncthing", "PBEM1_turn3_Bob"},
+		{"PBEM1_turn3_Bob (Alice's conflicted copy).se1", "Dropbox", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob (Alice's conflicted copy 2024-01-02).se1", "Dropbox", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob (DESKTOP-PC's conflicted copy 2024-01-02 1).se1", "Dropbox", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob (conflicted copy 2024-01-02 150405).se1", "Nextcloud", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob_conflict-20240102-150405.se1", "Nextcloud", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob-DESKTOP-AB12CD3.se1", "OneDrive", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1_turn3_Bob-LAPTOP-AB12CD3-2.se1", "OneDrive", "PBEM1_turn3_Bob.se1"},
+		{"PBEM1 turn3 Jörg Müller (Bob's conflicted copy).se1", "Dropbox", "PBEM1 turn3 Jörg Müller.se1"},
+		{"PBEM1_turn3_Bob.se1", "", ""},
+		{"PBEM1_turn3_Bob (1).se1", "", ""},
+		{"PBEM1_turn3_Bob (copy).se1", "", ""},
+		{"PBEM1_turn3_Bob.sync-conflict-2024.se1", "", ""},
+		{"PBEM1_turn3_conflicted_copy.se1", "", ""},
+		{".syncthing.PBEM1_turn3_Bob.se1.tmp", "", ""},
+	}
+	for _, tt := range tests {
+		c, ok := ParseConflict(tt.name)
+		if ok != (tt.tool != "") || c.Tool != tt.tool || c.Original != tt.original {
+			t.Errorf("ParseConflict(%q) = %+v, %v; want tool %q and original %q", tt.name, c, ok, tt.tool, tt.original)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package syncthing
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+)
+
+// Kinds of updates sent by Follower.Run
+const (
+	UpdateItem       = "item"       // a file finished syncing into the folder
+	UpdateCompletion = "completion" // a peer's copy of the folder progressed
+	UpdatePeer       = "peer"       // a peer connected or disconnected
+)
+
+// Update is something the monitor should react to.
+type Update struct {
+	Kind       string
+	Item       string  // path relative to the folder, for UpdateItem
+	Peer       Peer    // for UpdatePeer and UpdateCompletion
+	Completion float64 // percentage, for UpdateCompletion
+}
+
+// Follower turns the Syncthing events of one folder into updates.
+type Follower struct {
+	Client   *Client
+	FolderID string // folder to follow; empty to find it by Dir
+	Dir      string
+	Label    string // log prefix identifying the game
+}
+
+// retryDelay is the first delay before reconnecting to Syncthing; it doubles up to a minute
+var retryDelay = 5 * time.Second
+
+// followedEvents are the event types the follower subscribes to
+var followedEvents = []string{"ItemFinished", "FolderCompletion", "DeviceConnected", "DeviceDisconnected"}
+
+// Run sends updates to out until ctx is canceled. Disconnected peers are reported on
+// connecting to Syncthing and whenever a peer's connection changes. When Syncthing can't be
+// reached, Run keeps retrying with an increasing delay.
+func (f *Follower) Run(ctx context.Context, out chan<- Update) {
+	var folder Folder
+	peers := make(map[string]Peer)
+	since := -1
+	backoff := retryDelay
+	failing := false
+
+	send := func(u Update) bool {
+		select {
+		case out <- u:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+	fail := func(err error) bool {
+		if ctx.Err() != nil {
+			return false
+		}
+		if !failing {
+			log.Printf("⚠️ %sCannot follow Syncthing: %v; retrying in the background\n", f.Label, err)
+			failing = true
+		}
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return false
+		}
+		backoff = min(backoff*2, time.Minute)
+		return true
+	}
+
+	for ctx.Err() == nil {
+		// (Re)connect: find the folder, skip events from before now and check the peers
+		if since < 0 {
+			var err error
+			if folder, err = f.Client.FindFolder(ctx, f.FolderID, f.Dir); err != nil {
+				if !fail(err) {
+					return
+				}
+				continue
+			}
+			latest, err := f.Client.Events(ctx, 0, 1)
+			if err != nil {
+				if !fail(err) {
+					return
+				}
+				continue
+			}
+			since = 0
+			if len(latest) > 0 {
+				since = latest[len(latest)-1].ID
+			}
+			current, err := f.Client.Peers(ctx, folder)
+			if err != nil {
+				since = -1
+				if !fail(err) {
+					return
+				}
+				continue
+			}
+			log.Printf("🔗 %sFollowing Syncthing folder %s shared with %d peer(s)\n", f.Label, folder.ID, len(current))
+			for _, p := range current {
+				prev, known := peers[p.ID]
+				peers[p.ID] = p
+				if (known && prev.Connected != p.Connected) || (!known && !p.Connected) {
+					if !send(Update{Kind: UpdatePeer, Peer: p}) {
+						return
+					}
+				}
+			}
+			failing = false
+			backoff = retryDelay
+		}
+
+		events, err := f.Client.Events(ctx, since, 0, followedEvents...)
+		if err != nil {
+			// Syncthing may have restarted with new event IDs
+			since = -1
+			if !fail(err) {
+				return
+			}
+			continue
+		}
+		for _, ev := range events {
+			since = ev.ID
+			u, ok := f.update(ev, folder, peers)
+			if ok && !send(u) {
+				return
+			}
+		}
+	}
+}
+
+// update translates an event into an update for the followed folder, tracking peer connections
+func (f *Follower) update(ev Event, folder Folder, peers map[string]Peer) (Update, bool) {
+	switch ev.Type {
+	case "ItemFinished":
+		var d struct {
+			Item   string  `json:"item"`
+			Folder string  `json:"folder"`
+			Error  *string `json:"error"`
+			Type   string  `json:"type"`
+			Action string  `json:"action"`
+		}
+		if json.Unmarshal(ev.Data, &d) != nil || d.Folder != folder.ID || d.Error != nil || d.Type != "file" || d.Action == "delete" {
+			return Update{}, false
+		}
+		return Update{Kind: UpdateItem, Item: d.Item}, true
+	case "FolderCompletion":
+		var d struct {
+			Device     string  `json:"device"`
+			Folder     string  `json:"folder"`
+			Completion float64 `json:"completion"`
+		}
+		if json.Unmarshal(ev.Data, &d) != nil || d.Folder != folder.ID {
+			return Update{}, false
+		}
+		p, ok := peers[d.Device]
+		if !ok {
+			return Update{}, false
+		}
+		return Update{Kind: UpdateCompletion, Peer: p, Completion: d.Completion}, true
+	case "DeviceConnected", "DeviceDisconnected":
+		var d struct {
+			ID string `json:"id"`
+		}
+		if json.Unmarshal(ev.Data, &d) != nil {
+			return Update{}, false
+		}
+		p, ok := peers[d.ID]
+		connected := ev.Type == "DeviceConnected"
+		if !ok || p.Connected == connected {
+			return Update{}, false
+		}
+		p.Connected = connected
+		peers[d.ID] = p
+		return Update{Kind: UpdatePeer, Peer: p}, true
+	}
+	return Update{}, false
+}
@@ -0,0 +1,191 @@
+package syncthing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+const (
+	testKey = "test-key"
+	selfID  = "SELF-AAAAAAA"
+	peerA   = "PEERA-BBBBBBB"
+	peerB   = "PEERB-CCCCCCC"
+)
+
+// standIn serves the Syncthing REST endpoints the follower uses. Event requests are answered
+// from batches in order; a nil batch fails the request as if Syncthing had restarted, after
+// which event IDs start over at restartID and peer A is connected again.
+type standIn struct {
+	mu        sync.Mutex
+	latestID  int
+	restartID int
+	connected map[string]bool
+	batches   [][]Event
+	sinces    []int // since of every long-poll request
+}
+
+func (s *standIn) handler(t *testing.T) http.Handler {
+	mux := http.NewServeMux()
+	reply := func(w http.ResponseWriter, v any) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(v)
+	}
+	mux.HandleFunc("GET /rest/config/folders", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]any{
+			{"id": "other", "path": "/elsewhere", "devices": []map[string]string{{"deviceID": selfID}}},
+			{"id": "saves", "path": "/saves", "devices": []map[string]string{{"deviceID": selfID}, {"deviceID": peerA}, {"deviceID": peerB}}},
+		})
+	})
+	mux.HandleFunc("GET /rest/system/status", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, map[string]string{"myID": selfID})
+	})
+	mux.HandleFunc("GET /rest/config/devices", func(w http.ResponseWriter, r *http.Request) {
+		reply(w, []map[string]string{{"deviceID": selfID, "name": "bot"}, {"deviceID": peerA, "name": "alice-pc"}, {"deviceID": peerB}})
+	})
+	mux.HandleFunc("GET /rest/system/connections", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		conns := make(map[string]map[string]bool)
+		for id, c := range s.connected {
+			conns[id] = map[string]bool{"connected": c}
+		}
+		reply(w, map[string]any{"connections": conns})
+	})
+	mux.HandleFunc("GET /rest/events", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-API-Key") != testKey {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		q := r.URL.Query()
+		s.mu.Lock()
+		if q.Get("limit") == "1" {
+			latest := []Event{{ID: s.latestID, Type: "Starting"}}
+			s.mu.Unlock()
+			reply(w, latest)
+			return
+		}
+		since, _ := strconv.Atoi(q.Get("since"))
+		s.sinces = append(s.sinces, since)
+		if q.Get("events") != "ItemFinished,FolderCompletion,DeviceConnected,DeviceDisconnected" {
+			t.Errorf("subscribed to events %q", q.Get("events"))
+		}
+		if len(s.batches) == 0 {
+			s.mu.Unlock()
+			<-r.Context().Done()
+			return
+		}
+		batch := s.batches[0]
+		s.batches = s.batches[1:]
+		if batch == nil {
+			s.latestID = s.restartID
+			s.connected[peerA] = true
+			s.mu.Unlock()
+			http.Error(w, "restarting", http.StatusServiceUnavailable)
+			return
+		}
+		s.mu.Unlock()
+		reply(w, batch)
+	})
+	return mux
+}
+
+// event builds an event with the given data
+func event(id int, typ string, data map[string]any) Event {
+	raw, _ := json.Marshal(data)
+	return Event{ID: id, Type: typ, Data: raw}
+}
+
+func TestFollowerRun(t *testing.T) {
+	retryDelay = 10 * time.Millisecond
+	t.Cleanup(func() { retryDelay = 5 * time.Second })
+
+	s := &standIn{
+		latestID:  10,
+		restartID: 3,
+		connected: map[string]bool{peerA: true},
+		batches: [][]Event{
+			{
+				event(11, "ItemFinished", map[string]any{"folder": "saves", "item": "pbem1_turn2_bob.se1", "type": "file", "action": "update", "error": nil}),
+				event(12, "ItemFinished", map[string]any{"folder": "other", "item": "elsewhere.se1", "type": "file", "action": "update"}),
+				event(13, "ItemFinished", map[string]any{"folder": "saves", "item": "old.se1", "type": "file", "action": "delete"}),
+				event(14, "ItemFinished", map[string]any{"folder": "saves", "item": "broken.se1", "type": "file", "action": "update", "error": "disk full"}),
+				event(15, "FolderCompletion", map[string]any{"folder": "saves", "device": peerA, "completion": 50}),
+				event(16, "DeviceDisconnected", map[string]any{"id": peerA}),
+				event(17, "DeviceDisconnected", map[string]any{"id": peerA}),
+			},
+			nil, // Syncthing restarts
+			{
+				event(4, "ItemFinished", map[string]any{"folder": "saves", "item": "pbem1_turn3_alice.se1", "type": "file", "action": "update"}),
+			},
+		},
+	}
+	srv := httptest.NewServer(s.handler(t))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out := make(chan Update)
+	done := make(chan struct{})
+	f := &Follower{Client: NewClient(srv.URL, testKey), FolderID: "saves"}
+	go func() {
+		f.Run(ctx, out)
+		close(done)
+	}()
+
+	next := func() Update {
+		t.Helper()
+		select {
+		case u := <-out:
+			return u
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for an update")
+			return Update{}
+		}
+	}
+	describe := func(u Update) string {
+		return fmt.Sprintf("%s %s %s connected=%v %.0f", u.Kind, u.Item, u.Peer.Label(), u.Peer.Connected, u.Completion)
+	}
+	want := []string{
+		"peer  PEERB connected=false 0",                 // disconnected at startup
+		"item pbem1_turn2_bob.se1  connected=false 0",   // other folders, deletions and failures are dropped
+		"completion  alice-pc connected=true 50",        // a peer's progress
+		"peer  alice-pc connected=false 0",              // the repeated disconnect is dropped
+		"peer  alice-pc connected=true 0",               // seen reconnected after Syncthing restarted
+		"item pbem1_turn3_alice.se1  connected=false 0", // events after the restart's latest ID
+	}
+	for _, w := range want {
+		if got := describe(next()); got != w {
+			t.Errorf("update = %q, want %q", got, w)
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Run did not return after cancel")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.sinces) < 3 || s.sinces[0] != 10 || s.sinces[1] != 17 || s.sinces[2] != 3 {
+		t.Errorf("long polls used since %v, want 10, 17 then 3 after the restart", s.sinces)
+	}
+}
+
+func TestEventsRejectsAPIKey(t *testing.T) {
+	s := &standIn{connected: map[string]bool{}}
+	srv := httptest.NewServer(s.handler(t))
+	defer srv.Close()
+
+	_, err := NewClient(srv.URL, "wrong").Events(context.Background(), 0, 1)
+	if err == nil || err.Error() != "syncthing rejected the API key (status 403)" {
+		t.Errorf("Events with a wrong key: err = %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package syncthing
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the address of a local Syncthing GUI and REST API.
+const DefaultBaseURL = "http://127.0.0.1:8384"
+
+// eventTimeout is how long Syncthing holds an events request open without new events
+const eventTimeout = 60 * time.Second
+
+// Folder is the subset of a Syncthing folder configuration the bot needs.
+type Folder struct {
+	ID      string `json:"id"`
+	Label   string `json:"label"`
+	Path    string `json:"path"`
+	Devices []struct {
+		DeviceID string `json:"deviceID"`
+	} `json:"devices"`
+}
+
+// Peer is a remote device sharing the watched folder.
+type Peer struct {
+	ID        string
+	Name      string
+	Connected bool
+}
+
+// Label returns the device name, or a shortened device ID if it has none.
+func (p Peer) Label() string {
+	if p.Name != "" {
+		return p.Name
+	}
+	if i := strings.IndexByte(p.ID, '-'); i > 0 {
+		return p.ID[:i]
+	}
+	return p.ID
+}
+
+// Event is a single entry from the Syncthing events API.
+type Event struct {
+	ID   int             `json:"id"`
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// Client is a minimal Syncthing REST client authenticated with an API key.
+// BaseURL can point at a local stand-in server.
+type Client struct {
+	BaseURL string
+	APIKey  string
+	HTTP    *http.Client
+}
+
+// NewClient returns a client for the given API key. An empty baseURL selects DefaultBaseURL.
+func NewClient(baseURL, apiKey string) *Client {
+	if strings.TrimSpace(baseURL) == "" {
+		baseURL = DefaultBaseURL
+	}
+	return &Client{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		APIKey:  apiKey,
+		// Event requests are long polls bounded by their context instead
+		HTTP: &http.Client{},
+	}
+}
+
+// get requests path with query parameters and decodes the JSON response into v
+func (c *Client) get(ctx context.Context, path string, query url.Values, v any) error {
+	u := c.BaseURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-API-Key", c.APIKey)
+
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting Syncthing %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return fmt.Errorf("syncthing rejected the API key (status %d)", resp.StatusCode)
+	default:
+		return fmt.Errorf("syncthing returned status %d for %s: %s", resp.StatusCode, path, strings.TrimSpace(string(body)))
+	}
+	if err := json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("decoding Syncthing %s: %w", path, err)
+	}
+	return nil
+}
+
+// FindFolder returns the folder with the given ID or, when id is empty, the folder whose path is dir.
+func (c *Client) FindFolder(ctx context.Context, id, dir string) (Folder, error) {
+	var folders []Folder
+	if err := c.get(ctx, "/rest/config/folders", nil, &folders); err != nil {
+		return Folder{}, err
+	}
+	abs, _ := filepath.Abs(dir)
+	for _, f := range folders {
+		if id != "" && f.ID == id {
+			return f, nil
+		}
+		if id == "" && filepath.Clean(f.Path) == abs {
+			return f, nil
+		}
+	}
+	if id != "" {
+		return Folder{}, fmt.Errorf("syncthing has no folder with ID '%s'", id)
+	}
+	return Folder{}, fmt.Errorf("syncthing shares no folder at %s; set SYNCTHING_FOLDER to the folder ID", abs)
+}
+
+// Peers returns the remote devices sharing folder and whether they are connected.
+func (c *Client) Peers(ctx context.Context, folder Folder) ([]Peer, error) {
+	var self struct {
+		MyID string `json:"myID"`
+	}
+	if err := c.get(ctx, "/rest/system/status", nil, &self); err != nil {
+		return nil, err
+	}
+	var devices []struct {
+		DeviceID string `json:"deviceID"`
+		Name     string `json:"name"`
+	}
+	if err := c.get(ctx, "/rest/config/devices", nil, &devices); err != nil {
+		return nil, err
+	}
+	var conns struct {
+		Connections map[string]struct {
+			Connected bool `json:"connected"`
+		} `json:"connections"`
+	}
+	if err := c.get(ctx, "/rest/system/connections", nil, &conns); err != nil {
+		return nil, err
+	}
+
+	names := make(map[string]string, len(devices))
+	for _, d := range devices {
+		names[d.DeviceID] = d.Name
+	}
+	var peers []Peer
+	for _, d := range folder.Devices {
+		if d.DeviceID == self.MyID {
+			continue
+		}
+		peers = append(peers, Peer{ID: d.DeviceID, Name: names[d.DeviceID], Connected: conns.Connections[d.DeviceID].Connected})
+	}
+	return peers, nil
+}
+
+// Events waits for events after since, holding the request open for up to a minute.
+// A limit above zero returns only the most recent events.
+func (c *Client) Events(ctx context.Context, since, limit int, types ...string) ([]Event, error) {
+	q := url.Values{}
+	q.Set("since", strconv.Itoa(since))
+	q.Set("timeout", strconv.Itoa(int(eventTimeout.Seconds())))
+	if limit > 0 {
+		q.Set("limit", strconv.Itoa(limit))
+	}
+	if len(types) > 0 {
+		q.Set("events", strings.Join(types, ","))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, eventTimeout+15*time.Second)
+	defer cancel()
+	var events []Event
+	if err := c.get(ctx, "/rest/events", q, &events); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
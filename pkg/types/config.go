@@ -1,61 +1,570 @@
 package types
 
 import (
+	"flag"
+	"fmt"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/watcher"
+)
+
+// Auto-rename modes for misnamed save files
+const (
+	AutoRenameOff    = "off"
+	AutoRenameCopy   = "copy"
+	AutoRenameRename = "rename"
 )
 
-// Config holds all runtime configuration values loaded from environment variables.
+// Config holds all runtime configuration values loaded from flags, environment variables and the config file.
+// In multi-game mode there is one Config per game.
 type Config struct {
+	// ID identifies the game in multi-game mode; empty for a single game
+	ID string
+
 	// Raw values
 	UserMappingsRaw      string
+	RosterFile           string
 	GameName             string
+	GameProfile          string
 	WebhookURL           string
+	AdminWebhookURL      string
+	DiscordBotToken      string
+	DiscordAPIURL        string
+	DiscordGuildID       string
 	WatchDirectory       string
 	IgnorePatternsRaw    string
 	AllowedExtensionsRaw string
+	AutoRename           string
+	HTTPAddr             string
+	WatchMode            string
+	SyncthingURL         string
+	SyncthingAPIKey      string
+	SyncthingFolder      string
+	S3Endpoint           string
+	S3Region             string
+	S3AccessKeyID        string
+	S3SecretAccessKey    string
+	WebDAVUsername       string
+	WebDAVPassword       string
+
+	// ConfigChangeAlerts posts reload summaries to the admin channel
+	ConfigChangeAlerts bool
 
 	// Parsed values
-	IgnorePatterns          []string
-	AllowedExtensions       []string
-	FileDebounceMs          int
-	ReminderIntervalMinutes int
-	PollIntervalSec         int
+	IgnorePatterns    []string
+	AllowedExtensions []string
+	FileDebounce      time.Duration
+	ReminderInterval  time.Duration
+	PollInterval      time.Duration
+	RescanInterval    time.Duration
+
+	// Players holds roster entries given inline in the config file
+	Players []userparser.Player
+
+	// ForeignGameNames lists the game names of other games sharing this watch directory;
+	// their saves are skipped instead of being reported as misnamed
+	ForeignGameNames []string
+
+	// Sources records where each setting's value came from
+	Sources map[string]Source
+
+	// problems holds values that could not be parsed, reported by Validate
+	problems []Problem
+}
+
+// Setting describes a configuration key. Keys are named after their environment variable;
+// the config file uses the lowercase form and flags the kebab-case form (e.g. --game-name).
+// Secret settings can also be read from a file named by the same key with a _FILE suffix.
+type Setting struct {
+	Key     string
+	Default string
+	Usage   string
+	Secret  bool
 }
 
-// LoadConfigFromEnv reads environment variables and returns a populated Config with defaults applied.
-func LoadConfigFromEnv() Config {
-	cfg := Config{}
+// Settings lists every per-game configuration key in display order.
+var Settings = []Setting{
+	{Key: "GAME_NAME", Default: "pbem1", Usage: "name prefix for save files"},
+	{Key: "GAME_PROFILE", Default: game.DefaultProfile, Usage: "game profile: " + strings.Join(game.Names(), ", ")},
+	{Key: "WATCH_DIRECTORY", Default: "./data", Usage: "directory to monitor for save files, s3://bucket/prefix for a bucket or webdavs://host/path for a WebDAV folder"},
+	{Key: "USER_MAPPINGS", Usage: "comma-separated 'order username discordId' player mappings"},
+	{Key: "ROSTER_FILE", Usage: "path to a YAML or JSON roster file"},
+	{Key: "DISCORD_WEBHOOK_URL", Usage: "Discord webhook URL for notifications", Secret: true},
+	{Key: "ADMIN_WEBHOOK_URL", Usage: "Discord webhook URL for admin alerts", Secret: true},
+	{Key: "DISCORD_BOT_TOKEN", Usage: "Discord bot token used to verify players", Secret: true},
+	{Key: "DISCORD_API_URL", Usage: "Discord REST API base URL"},
+	{Key: "DISCORD_GUILD_ID", Usage: "Discord server ID; players must be members and their server nicknames are used"},
+	{Key: "IGNORE_PATTERNS", Usage: "comma-separated filename patterns to ignore"},
+	{Key: "ALLOWED_EXTENSIONS", Usage: "comma-separated save file extensions (default from profile)"},
+	{Key: "AUTO_RENAME", Default: AutoRenameOff, Usage: "fix misnamed saves: off, copy or rename"},
+	{Key: "FILE_DEBOUNCE_MS", Default: "30s", Usage: "time to wait before processing a new file, e.g. 30s (plain numbers are milliseconds)"},
+	{Key: "REMINDER_INTERVAL_MINUTES", Default: "12h", Usage: "time between turn reminders, e.g. 12h (plain numbers are minutes)"},
+	{Key: "POLL_INTERVAL_SEC", Default: "5s", Usage: "time between directory scans, e.g. 5s (plain numbers are seconds)"},
+	{Key: "WATCH_MODE", Default: watcher.ModeAuto, Usage: "how to detect new files: auto, notify or poll (auto polls network filesystems)"},
+	{Key: "RESCAN_INTERVAL", Default: "5m", Usage: "time between full directory scans when using file notifications, e.g. 5m (plain numbers are seconds)"},
+	{Key: "SYNCTHING_API_KEY", Usage: "Syncthing API key; enables processing saves as soon as Syncthing finishes syncing them", Secret: true},
+	{Key: "SYNCTHING_URL", Usage: "Syncthing REST API address (default http://127.0.0.1:8384)"},
+	{Key: "SYNCTHING_FOLDER", Usage: "Syncthing folder ID of the watch directory (default: found by path)"},
+	{Key: "S3_ENDPOINT", Usage: "S3-compatible service URL, e.g. http://minio:9000 (default AWS)"},
+	{Key: "S3_REGION", Usage: "S3 region (default us-east-1)"},
+	{Key: "S3_ACCESS_KEY_ID", Usage: "S3 access key ID for an s3:// watch directory"},
+	{Key: "S3_SECRET_ACCESS_KEY", Usage: "S3 secret access key for an s3:// watch directory", Secret: true},
+	{Key: "WEBDAV_USERNAME", Usage: "user name for a webdav:// or webdavs:// watch directory"},
+	{Key: "WEBDAV_PASSWORD", Usage: "password or app password for a webdav:// or webdavs:// watch directory", Secret: true},
+	{Key: "HTTP_ADDR", Usage: "address for the HTTP status server, e.g. :8080"},
+	{Key: "CONFIG_CHANGE_ALERTS", Default: "false", Usage: "post configuration reload summaries to the admin channel (true or false)"},
+}
 
-	cfg.UserMappingsRaw = os.Getenv("USER_MAPPINGS")
-	cfg.GameName = firstNonEmpty(os.Getenv("GAME_NAME"), "pbem1")
-	cfg.WebhookURL = os.Getenv("DISCORD_WEBHOOK_URL")
-	cfg.WatchDirectory = firstNonEmpty(os.Getenv("WATCH_DIRECTORY"), "./data")
-	cfg.IgnorePatternsRaw = os.Getenv("IGNORE_PATTERNS")
-	cfg.AllowedExtensionsRaw = firstNonEmpty(os.Getenv("ALLOWED_EXTENSIONS"), "se1")
+// Kinds of configuration sources, from lowest to highest precedence
+const (
+	SourceDefault = "default"
+	SourceFile    = "file"
+	SourceEnv     = "env"
+	SourceFlag    = "flag"
+	SourceInput   = "input" // entered in the setup wizard
+)
+
+// Source records where a configuration value came from.
+type Source struct {
+	Kind string // one of the Source* constants
+	Name string // variable, file or flag name
+}
+
+// String formats the source for logs, e.g. "env GAME_NAME".
+func (s Source) String() string {
+	if s.Name == "" {
+		return s.Kind
+	}
+	return s.Kind + " " + s.Name
+}
+
+// IsDefault reports whether the value was not configured anywhere.
+func (s Source) IsDefault() bool { return s.Kind == SourceDefault }
+
+// LoadOptions selects the configuration sources besides the environment.
+type LoadOptions struct {
+	// ConfigFile is an optional YAML config file
+	ConfigFile string
+	// Flags holds values of explicitly set command-line flags, keyed by setting
+	Flags map[string]string
+}
+
+// LoadConfigFromEnv reads environment variables and returns the first game's Config with defaults applied.
+func LoadConfigFromEnv() (Config, error) {
+	games, err := Load(LoadOptions{})
+	if err != nil {
+		return Config{}, err
+	}
+	return games[0], nil
+}
+
+// Load resolves the configuration of every game. Values are taken from flags, then the
+// environment, then the config file, then defaults. Within the environment and the file,
+// per-game values (GAME_<ID>_<VAR>, or a games entry) take priority over global ones.
+//
+// Games are listed in the comma-separated GAMES variable or the config file's games
+// section; without either, a single game is configured. GAME_NAME defaults to the game ID.
+func Load(opts LoadOptions) ([]Config, error) {
+	file := &fileConfig{}
+	if opts.ConfigFile != "" {
+		var err error
+		if file, err = readConfigFile(opts.ConfigFile); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := parseCSV(os.Getenv("GAMES"))
+	if len(ids) == 0 {
+		for _, g := range file.games {
+			ids = append(ids, g.id)
+		}
+	}
+	if len(ids) == 0 {
+		return []Config{loadGame("", opts.Flags, file, nil)}, nil
+	}
+
+	games := make([]Config, 0, len(ids))
+	for _, id := range ids {
+		games = append(games, loadGame(id, opts.Flags, file, file.game(id)))
+	}
+
+	// All games share one status server
+	for _, g := range games[1:] {
+		if g.HTTPAddr != games[0].HTTPAddr {
+			return nil, fmt.Errorf("HTTP_ADDR must be the same for every game since they share one status server, but game %s uses '%s' and game %s uses '%s'; set it globally",
+				games[0].ID, games[0].HTTPAddr, g.ID, g.HTTPAddr)
+		}
+	}
+
+	// Let games sharing a directory skip each other's saves
+	for i := range games {
+		for j := range games {
+			if i != j && SameDirectory(games[i].WatchDirectory, games[j].WatchDirectory) {
+				games[i].ForeignGameNames = append(games[i].ForeignGameNames, strings.ToLower(games[j].GameName))
+			}
+		}
+	}
+	return games, nil
+}
+
+// FromValues builds a Config from setting values keyed by setting, applying defaults
+// for the rest, so answers can be checked with Validate before they are saved.
+func FromValues(values map[string]string) Config {
+	return loadConfig(func(key string) (string, Source, error) {
+		if v := values[key]; v != "" {
+			return v, Source{Kind: SourceInput}, nil
+		}
+		return defaultFor(key), Source{Kind: SourceDefault}, nil
+	})
+}
+
+// loadGame resolves one game's settings through all configuration layers
+func loadGame(id string, flags map[string]string, file *fileConfig, section *fileSection) Config {
+	// Layers in order of precedence
+	layers := []func(key string) (string, Source, bool){
+		func(key string) (string, Source, bool) {
+			v, ok := flags[key]
+			return v, Source{SourceFlag, "--" + FlagName(key)}, ok
+		},
+		func(key string) (string, Source, bool) {
+			if id == "" {
+				return "", Source{}, false
+			}
+			name := "GAME_" + EnvKey(id) + "_" + key
+			return os.Getenv(name), Source{SourceEnv, name}, os.Getenv(name) != ""
+		},
+		func(key string) (string, Source, bool) {
+			return os.Getenv(key), Source{SourceEnv, key}, os.Getenv(key) != ""
+		},
+		func(key string) (string, Source, bool) {
+			if section == nil {
+				return "", Source{}, false
+			}
+			v, ok := section.values[key]
+			return v, Source{SourceFile, file.path + " (games." + id + ")"}, ok
+		},
+		func(key string) (string, Source, bool) {
+			v, ok := file.global.values[key]
+			return v, Source{SourceFile, file.path}, ok
+		},
+	}
+
+	resolve := func(key string) (string, Source, error) {
+		for _, layer := range layers {
+			if v, src, ok := layer(key); ok {
+				return v, src, nil
+			}
+			// Secrets may be given as a path to a file holding the value
+			if IsSecret(key) {
+				if path, src, ok := layer(key + "_FILE"); ok {
+					src.Name += " (" + path + ")"
+					v, err := readSecretFile(path)
+					return v, src, err
+				}
+			}
+		}
+		if key == "GAME_NAME" && id != "" {
+			return id, Source{Kind: SourceDefault}, nil
+		}
+		return defaultFor(key), Source{Kind: SourceDefault}, nil
+	}
+
+	cfg := loadConfig(resolve)
+	cfg.ID = id
+
+	// Players from the config file stand in for USER_MAPPINGS unless it was set more specifically
+	if cfg.Sources["USER_MAPPINGS"].Kind != SourceFlag && cfg.Sources["USER_MAPPINGS"].Kind != SourceEnv {
+		if section != nil && len(section.players) > 0 {
+			cfg.Players = section.players
+		} else if len(file.global.players) > 0 {
+			cfg.Players = file.global.players
+		}
+	}
+	return cfg
+}
+
+// readSecretFile reads a secret from a file such as a Docker or Kubernetes secret,
+// dropping surrounding whitespace and the trailing newline
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading secret file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// defaultFor returns the default value of a setting
+func defaultFor(key string) string {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s.Default
+		}
+	}
+	return ""
+}
+
+// IsSecret reports whether a setting holds a credential that must not be logged.
+func IsSecret(key string) bool {
+	for _, s := range Settings {
+		if s.Key == key {
+			return s.Secret
+		}
+	}
+	return false
+}
+
+// FlagName converts a setting key into its command-line flag name, e.g. GAME_NAME -> game-name.
+func FlagName(key string) string {
+	return strings.ReplaceAll(strings.ToLower(key), "_", "-")
+}
+
+// RegisterFlags defines a string flag for every setting on fs. The returned function
+// reports the flags that were set explicitly, keyed by setting.
+func RegisterFlags(fs *flag.FlagSet) func() map[string]string {
+	values := make(map[string]*string, len(Settings))
+	for _, s := range Settings {
+		values[s.Key] = fs.String(FlagName(s.Key), "", s.Usage)
+		if s.Secret {
+			values[s.Key+"_FILE"] = fs.String(FlagName(s.Key+"_FILE"), "", "file containing the "+strings.ToLower(s.Key))
+		}
+	}
+	return func() map[string]string {
+		set := make(map[string]string)
+		fs.Visit(func(f *flag.Flag) {
+			for key, v := range values {
+				if FlagName(key) == f.Name {
+					set[key] = *v
+				}
+			}
+		})
+		return set
+	}
+}
+
+// EnvKey converts a game ID into the form used in per-game variable names, e.g. "my-game" -> "MY_GAME".
+func EnvKey(id string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(strings.TrimSpace(id)) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	return b.String()
+}
+
+// SameDirectory reports whether two paths refer to the same directory after cleaning.
+func SameDirectory(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	if errA != nil || errB != nil {
+		return filepath.Clean(a) == filepath.Clean(b)
+	}
+	return absA == absB
+}
+
+// Label returns a short prefix identifying the game in log output, or "" for a single game.
+func (c Config) Label() string {
+	if c.ID == "" {
+		return ""
+	}
+	return "[" + c.ID + "] "
+}
+
+func loadConfig(resolve func(string) (string, Source, error)) Config {
+	cfg := Config{Sources: make(map[string]Source, len(Settings))}
+	get := func(key string) string {
+		v, src, err := resolve(key)
+		cfg.Sources[key] = src
+		if err != nil {
+			cfg.problems = append(cfg.problems, Problem{
+				Key:     key,
+				Message: fmt.Sprintf("%v (from %s)", err, src),
+				Fatal:   true,
+			})
+		}
+		if IsSecret(key) {
+			redact.Register(v)
+		}
+		return v
+	}
+
+	cfg.UserMappingsRaw = get("USER_MAPPINGS")
+	cfg.RosterFile = get("ROSTER_FILE")
+	cfg.GameName = get("GAME_NAME")
+	cfg.GameProfile = get("GAME_PROFILE")
+	cfg.WebhookURL = get("DISCORD_WEBHOOK_URL")
+	cfg.AdminWebhookURL = get("ADMIN_WEBHOOK_URL")
+	cfg.DiscordBotToken = get("DISCORD_BOT_TOKEN")
+	cfg.DiscordAPIURL = get("DISCORD_API_URL")
+	cfg.DiscordGuildID = get("DISCORD_GUILD_ID")
+	cfg.WatchDirectory = get("WATCH_DIRECTORY")
+	cfg.IgnorePatternsRaw = get("IGNORE_PATTERNS")
+	cfg.AllowedExtensionsRaw = get("ALLOWED_EXTENSIONS")
+	cfg.AutoRename = strings.ToLower(get("AUTO_RENAME"))
+	cfg.HTTPAddr = get("HTTP_ADDR")
+	cfg.WatchMode = strings.ToLower(get("WATCH_MODE"))
+	cfg.SyncthingURL = get("SYNCTHING_URL")
+	cfg.SyncthingAPIKey = get("SYNCTHING_API_KEY")
+	cfg.SyncthingFolder = get("SYNCTHING_FOLDER")
+	cfg.S3Endpoint = get("S3_ENDPOINT")
+	cfg.S3Region = get("S3_REGION")
+	cfg.S3AccessKeyID = get("S3_ACCESS_KEY_ID")
+	cfg.S3SecretAccessKey = get("S3_SECRET_ACCESS_KEY")
+	cfg.WebDAVUsername = get("WEBDAV_USERNAME")
+	cfg.WebDAVPassword = get("WEBDAV_PASSWORD")
+	if raw := get("CONFIG_CHANGE_ALERTS"); strings.TrimSpace(raw) != "" {
+		b, err := strconv.ParseBool(strings.TrimSpace(raw))
+		if err != nil {
+			cfg.problems = append(cfg.problems, Problem{
+				Key:     "CONFIG_CHANGE_ALERTS",
+				Message: fmt.Sprintf("'%s' is not true or false (from %s)", raw, cfg.Sources["CONFIG_CHANGE_ALERTS"]),
+				Fatal:   true,
+			})
+		}
+		cfg.ConfigChangeAlerts = b
+	}
 
 	// Parse lists
 	cfg.IgnorePatterns = parseCSVLower(cfg.IgnorePatternsRaw)
-	cfg.AllowedExtensions = parseCSVLower(cfg.AllowedExtensionsRaw)
+	cfg.AllowedExtensions = parseExtensions(cfg.AllowedExtensionsRaw)
+	if len(cfg.AllowedExtensions) == 0 {
+		// Fall back to the save extensions of the selected game profile
+		cfg.AllowedExtensions = cfg.Profile().Extensions
+	}
 
-	// Numbers with defaults
-	cfg.FileDebounceMs = parseIntOrDefault(os.Getenv("FILE_DEBOUNCE_MS"), 30000)
-	cfg.ReminderIntervalMinutes = parseIntOrDefault(os.Getenv("REMINDER_INTERVAL_MINUTES"), 720)
-	cfg.PollIntervalSec = parseIntOrDefault(os.Getenv("POLL_INTERVAL_SEC"), 5)
+	// Durations with defaults; plain numbers use each setting's legacy unit and
+	// unparsable values are reported by Validate
+	dur := func(key string, legacyUnit time.Duration) time.Duration {
+		raw := get(key)
+		if strings.TrimSpace(raw) == "" {
+			raw = defaultFor(key)
+		}
+		d, err := duration.Parse(raw, legacyUnit)
+		if err != nil {
+			cfg.problems = append(cfg.problems, Problem{
+				Key:     key,
+				Message: fmt.Sprintf("%v (from %s)", err, cfg.Sources[key]),
+				Fatal:   true,
+			})
+			d, _ = duration.Parse(defaultFor(key), legacyUnit)
+		}
+		return d
+	}
+	cfg.FileDebounce = dur("FILE_DEBOUNCE_MS", time.Millisecond)
+	cfg.ReminderInterval = dur("REMINDER_INTERVAL_MINUTES", time.Minute)
+	cfg.PollInterval = dur("POLL_INTERVAL_SEC", time.Second)
+	cfg.RescanInterval = dur("RESCAN_INTERVAL", time.Second)
 
 	return cfg
 }
 
-func firstNonEmpty(vals ...string) string {
-	for _, v := range vals {
-		if strings.TrimSpace(v) != "" {
-			return v
-		}
+// Setting returns the raw value of a setting by key.
+func (c Config) Setting(key string) string {
+	switch key {
+	case "USER_MAPPINGS":
+		return c.UserMappingsRaw
+	case "ROSTER_FILE":
+		return c.RosterFile
+	case "GAME_NAME":
+		return c.GameName
+	case "GAME_PROFILE":
+		return c.GameProfile
+	case "DISCORD_WEBHOOK_URL":
+		return c.WebhookURL
+	case "ADMIN_WEBHOOK_URL":
+		return c.AdminWebhookURL
+	case "DISCORD_BOT_TOKEN":
+		return c.DiscordBotToken
+	case "DISCORD_API_URL":
+		return c.DiscordAPIURL
+	case "DISCORD_GUILD_ID":
+		return c.DiscordGuildID
+	case "WATCH_DIRECTORY":
+		return c.WatchDirectory
+	case "IGNORE_PATTERNS":
+		return c.IgnorePatternsRaw
+	case "ALLOWED_EXTENSIONS":
+		return c.AllowedExtensionsRaw
+	case "AUTO_RENAME":
+		return c.AutoRename
+	case "HTTP_ADDR":
+		return c.HTTPAddr
+	case "WATCH_MODE":
+		return c.WatchMode
+	case "SYNCTHING_URL":
+		return c.SyncthingURL
+	case "SYNCTHING_API_KEY":
+		return c.SyncthingAPIKey
+	case "SYNCTHING_FOLDER":
+		return c.SyncthingFolder
+	case "S3_ENDPOINT":
+		return c.S3Endpoint
+	case "S3_REGION":
+		return c.S3Region
+	case "S3_ACCESS_KEY_ID":
+		return c.S3AccessKeyID
+	case "S3_SECRET_ACCESS_KEY":
+		return c.S3SecretAccessKey
+	case "WEBDAV_USERNAME":
+		return c.WebDAVUsername
+	case "WEBDAV_PASSWORD":
+		return c.WebDAVPassword
+	case "CONFIG_CHANGE_ALERTS":
+		return strconv.FormatBool(c.ConfigChangeAlerts)
+	case "FILE_DEBOUNCE_MS":
+		return duration.Short(c.FileDebounce)
+	case "REMINDER_INTERVAL_MINUTES":
+		return duration.Short(c.ReminderInterval)
+	case "POLL_INTERVAL_SEC":
+		return duration.Short(c.PollInterval)
+	case "RESCAN_INTERVAL":
+		return duration.Short(c.RescanInterval)
 	}
 	return ""
 }
 
+// StoreOptions returns the settings for opening a remote watch directory.
+func (c Config) StoreOptions() store.Options {
+	return store.Options{
+		S3Endpoint:  c.S3Endpoint,
+		S3Region:    c.S3Region,
+		S3AccessKey: c.S3AccessKeyID,
+		S3SecretKey: c.S3SecretAccessKey,
+
+		WebDAVUsername: c.WebDAVUsername,
+		WebDAVPassword: c.WebDAVPassword,
+	}
+}
+
+// Profile returns the configured game profile, or the default profile if the ID is unknown.
+func (c Config) Profile() game.Profile {
+	if p, ok := game.Lookup(c.GameProfile); ok {
+		return p
+	}
+	return game.Default()
+}
+
+func parseCSV(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if v := strings.TrimSpace(p); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
 func parseCSVLower(s string) []string {
 	if strings.TrimSpace(s) == "" {
 		return []string{}
@@ -71,12 +580,14 @@ func parseCSVLower(s string) []string {
 	return out
 }
 
-func parseIntOrDefault(s string, def int) int {
-	if strings.TrimSpace(s) == "" {
-		return def
-	}
-	if n, err := strconv.Atoi(strings.TrimSpace(s)); err == nil {
-		return n
+// parseExtensions parses a comma separated list of extensions, accepting them with or without a leading dot
+func parseExtensions(s string) []string {
+	exts := parseCSVLower(s)
+	out := exts[:0]
+	for _, ext := range exts {
+		if ext = strings.TrimPrefix(ext, "."); ext != "" {
+			out = append(out, ext)
+		}
 	}
-	return def
+	return out
 }
@@ -0,0 +1,223 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+)
+
+func TestLoadSharedHTTPAddr(t *testing.T) {
+	t.Setenv("GAMES", "alpha,beta")
+	t.Setenv("HTTP_ADDR", ":8080")
+
+	games, err := Load(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load with a global HTTP_ADDR: %v", err)
+	}
+	for _, g := range games {
+		if g.HTTPAddr != ":8080" {
+			t.Errorf("game %s HTTPAddr = %q", g.ID, g.HTTPAddr)
+		}
+	}
+
+	t.Setenv("GAME_BETA_HTTP_ADDR", ":9090")
+	if _, err := Load(LoadOptions{}); err == nil || !strings.Contains(err.Error(), "HTTP_ADDR") {
+		t.Errorf("Load with differing HTTP_ADDR: err = %v, want an HTTP_ADDR error", err)
+	}
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	layers := []struct {
+		name   string
+		source Source
+	}{
+		{"flag", Source{SourceFlag, "--ignore-patterns"}},
+		{"game env", Source{SourceEnv, "GAME_ALPHA_IGNORE_PATTERNS"}},
+		{"env", Source{SourceEnv, "IGNORE_PATTERNS"}},
+		{"file game", Source{SourceFile, "(games.alpha)"}},
+		{"file global", Source{SourceFile, ""}},
+		{"default", Source{Kind: SourceDefault}},
+	}
+	for top, layer := range layers {
+		t.Run(layer.name, func(t *testing.T) {
+			// Set the value in this layer and every layer below it
+			set := func(i int) bool { return i >= top }
+			value := func(i int) string { return "from " + layers[i].name }
+
+			path := filepath.Join(t.TempDir(), "bot.yaml")
+			var file strings.Builder
+			if set(4) {
+				fmt.Fprintf(&file, "ignore_patterns: %s\n", value(4))
+			}
+			file.WriteString("games:\n  - id: alpha\n")
+			if set(3) {
+				fmt.Fprintf(&file, "    ignore_patterns: %s\n", value(3))
+			}
+			if err := os.WriteFile(path, []byte(file.String()), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			if set(2) {
+				t.Setenv("IGNORE_PATTERNS", value(2))
+			}
+			if set(1) {
+				t.Setenv("GAME_ALPHA_IGNORE_PATTERNS", value(1))
+			}
+			flags := map[string]string{}
+			if set(0) {
+				flags["IGNORE_PATTERNS"] = value(0)
+			}
+
+			games, err := Load(LoadOptions{ConfigFile: path, Flags: flags})
+			if err != nil {
+				t.Fatal(err)
+			}
+			g := games[0]
+			want := ""
+			if layer.name != "default" {
+				want = value(top)
+			}
+			if g.IgnorePatternsRaw != want {
+				t.Errorf("IGNORE_PATTERNS = %q, want %q", g.IgnorePatternsRaw, want)
+			}
+
+			src := g.Sources["IGNORE_PATTERNS"]
+			wantName := layer.source.Name
+			if layer.source.Kind == SourceFile {
+				wantName = strings.TrimSpace(path + " " + layer.source.Name)
+			}
+			if src.Kind != layer.source.Kind || src.Name != wantName {
+				t.Errorf("source = %q, want %q", src, Source{layer.source.Kind, wantName})
+			}
+		})
+	}
+}
+
+func TestLoadGames(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.yaml")
+	config := `watch_directory: /saves
+reminder_interval_minutes: 2h
+players:
+  - {order: 1, name: Alice, discord_id: "123456789012345678"}
+games:
+  - id: alpha
+    game_name: Campaign
+    players:
+      - {order: 1, name: Bob, discord_id: "223456789012345678"}
+  - id: beta
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FILE_DEBOUNCE_MS", "45s")
+	t.Setenv("GAME_BETA_FILE_DEBOUNCE_MS", "1m")
+	t.Setenv("GAME_BETA_USER_MAPPINGS", "1 Carol 323456789012345678")
+
+	games, err := Load(LoadOptions{ConfigFile: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(games) != 2 || games[0].ID != "alpha" || games[1].ID != "beta" {
+		t.Fatalf("Load returned %d games, want alpha and beta from the file", len(games))
+	}
+	alpha, beta := games[0], games[1]
+
+	// The game name defaults to the ID
+	if alpha.GameName != "Campaign" || beta.GameName != "beta" || !beta.Sources["GAME_NAME"].IsDefault() {
+		t.Errorf("game names = %q (%s), %q (%s); want Campaign from the file and beta by default",
+			alpha.GameName, alpha.Sources["GAME_NAME"], beta.GameName, beta.Sources["GAME_NAME"])
+	}
+
+	// Global values apply to every game unless a game overrides them
+	for _, g := range games {
+		if g.WatchDirectory != "/saves" || g.ReminderInterval != 2*time.Hour {
+			t.Errorf("game %s: WATCH_DIRECTORY = %q, REMINDER_INTERVAL = %s; want the file's global values", g.ID, g.WatchDirectory, g.ReminderInterval)
+		}
+	}
+	if alpha.FileDebounce != 45*time.Second || beta.FileDebounce != time.Minute {
+		t.Errorf("FILE_DEBOUNCE = %s and %s, want 45s from the environment and 1m for beta", alpha.FileDebounce, beta.FileDebounce)
+	}
+	if src := beta.Sources["FILE_DEBOUNCE_MS"]; src != (Source{SourceEnv, "GAME_BETA_FILE_DEBOUNCE_MS"}) {
+		t.Errorf("beta FILE_DEBOUNCE source = %s", src)
+	}
+
+	// A game's players replace the global ones; USER_MAPPINGS in the environment replaces both
+	if len(alpha.Players) != 1 || alpha.Players[0].Name != "Bob" {
+		t.Errorf("alpha players = %+v, want Bob from its games entry", alpha.Players)
+	}
+	if len(beta.Players) != 0 || beta.UserMappingsRaw == "" {
+		t.Errorf("beta players = %+v, USER_MAPPINGS = %q; want USER_MAPPINGS from the environment", beta.Players, beta.UserMappingsRaw)
+	}
+}
+
+func TestLoadSecretFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeSecret := func(name, value string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	hookFile := writeSecret("webhook", "https://discord.com/api/webhooks/1/from-file\n")
+	tokenFile := writeSecret("token", "  token-from-file-123  \n")
+	betaFile := writeSecret("beta-webhook", "https://discord.com/api/webhooks/2/beta-file")
+	configPath := writeSecret("bot.yaml", "discord_bot_token_file: "+tokenFile+"\ngames:\n  - id: alpha\n  - id: beta\n")
+
+	t.Setenv("DISCORD_WEBHOOK_URL_FILE", hookFile)
+	t.Setenv("GAME_BETA_DISCORD_WEBHOOK_URL_FILE", betaFile)
+	t.Setenv("ADMIN_WEBHOOK_URL", "https://discord.com/api/webhooks/3/direct")
+	t.Setenv("ADMIN_WEBHOOK_URL_FILE", filepath.Join(dir, "missing"))
+
+	games, err := Load(LoadOptions{ConfigFile: configPath})
+	if err != nil {
+		t.Fatal(err)
+	}
+	alpha, beta := games[0], games[1]
+
+	// Values are read from the file without surrounding whitespace
+	if alpha.WebhookURL != "https://discord.com/api/webhooks/1/from-file" {
+		t.Errorf("DISCORD_WEBHOOK_URL = %q, want the file's contents", alpha.WebhookURL)
+	}
+	if src := alpha.Sources["DISCORD_WEBHOOK_URL"]; src != (Source{SourceEnv, "DISCORD_WEBHOOK_URL_FILE (" + hookFile + ")"}) {
+		t.Errorf("DISCORD_WEBHOOK_URL source = %s", src)
+	}
+	if alpha.DiscordBotToken != "token-from-file-123" || alpha.Sources["DISCORD_BOT_TOKEN"].Kind != SourceFile {
+		t.Errorf("DISCORD_BOT_TOKEN = %q from %s, want the token from the config file's secret file", alpha.DiscordBotToken, alpha.Sources["DISCORD_BOT_TOKEN"])
+	}
+
+	// A per-game secret file beats the global one
+	if beta.WebhookURL != "https://discord.com/api/webhooks/2/beta-file" {
+		t.Errorf("beta DISCORD_WEBHOOK_URL = %q, want its own file's contents", beta.WebhookURL)
+	}
+
+	// The value itself beats a secret file in the same layer, which is not read
+	if alpha.AdminWebhookURL != "https://discord.com/api/webhooks/3/direct" {
+		t.Errorf("ADMIN_WEBHOOK_URL = %q, want the direct value", alpha.AdminWebhookURL)
+	}
+	if problems := problemsFor(t, alpha, "ADMIN_WEBHOOK_URL"); len(problems) != 0 {
+		t.Errorf("ADMIN_WEBHOOK_URL problems = %v, want none", problems)
+	}
+
+	// Secrets read from files are redacted like any other
+	if got := redact.String("token " + alpha.DiscordBotToken); got != "token "+redact.Placeholder {
+		t.Errorf("secret from a file was not redacted: %q", got)
+	}
+}
+
+func TestLoadMissingSecretFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	c := loadTestGame(t, map[string]string{
+		"USER_MAPPINGS":          "1 Alice 123456789012345678",
+		"DISCORD_BOT_TOKEN_FILE": missing,
+	})
+	problems := problemsFor(t, c, "DISCORD_BOT_TOKEN")
+	if len(problems) != 1 || !problems[0].Fatal || !strings.Contains(problems[0].Message, "reading secret file") || !strings.Contains(problems[0].Message, missing) {
+		t.Errorf("Validate with a missing secret file: problems = %v, want one fatal problem naming the file", problems)
+	}
+}
@@ -10,11 +10,11 @@ type DiscordWebhook struct {
 
 // Embed represents an embedded rich content section in a Discord message
 type Embed struct {
-	Color     int       `json:"color"`
-	Thumbnail Thumbnail `json:"thumbnail"`
-	Fields    []Field   `json:"fields"`
-	Footer    Footer    `json:"footer"`
-	Timestamp string    `json:"timestamp"`
+	Color     int        `json:"color"`
+	Thumbnail *Thumbnail `json:"thumbnail,omitempty"`
+	Fields    []Field    `json:"fields"`
+	Footer    Footer     `json:"footer"`
+	Timestamp string     `json:"timestamp"`
 }
 
 // Thumbnail represents an image thumbnail in a Discord embed
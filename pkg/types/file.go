@@ -0,0 +1,146 @@
+package types
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"gopkg.in/yaml.v3"
+)
+
+// fileConfig is a parsed YAML config file
+type fileConfig struct {
+	path   string
+	global fileSection
+	games  []fileSection
+}
+
+// fileSection holds the settings of the file's top level or of one games entry
+type fileSection struct {
+	id      string
+	values  map[string]string
+	players []userparser.Player
+}
+
+// game returns the section for the game with the given ID, or nil
+func (f *fileConfig) game(id string) *fileSection {
+	for i := range f.games {
+		if strings.EqualFold(f.games[i].id, id) {
+			return &f.games[i]
+		}
+	}
+	return nil
+}
+
+// readConfigFile parses a YAML config file. Keys are lowercase setting names
+// (e.g. game_name); lists are joined with commas. Unknown keys are rejected.
+func readConfigFile(path string) (*fileConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	f := &fileConfig{path: path, global: fileSection{values: map[string]string{}}}
+	if len(doc.Content) == 0 {
+		return f, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("invalid config file %s: expected a mapping of settings", path)
+	}
+
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i], root.Content[i+1]
+		if key.Value != "games" {
+			continue
+		}
+		if val.Kind != yaml.SequenceNode {
+			return nil, fmt.Errorf("%s:%d: games must be a list", path, val.Line)
+		}
+		for _, g := range val.Content {
+			sec, err := parseSection(path, g, true)
+			if err != nil {
+				return nil, err
+			}
+			if sec.id == "" {
+				return nil, fmt.Errorf("%s:%d: game entry is missing an id", path, g.Line)
+			}
+			if f.game(sec.id) != nil {
+				return nil, fmt.Errorf("%s:%d: duplicate game id '%s'", path, g.Line, sec.id)
+			}
+			f.games = append(f.games, *sec)
+		}
+	}
+
+	global, err := parseSection(path, root, false)
+	if err != nil {
+		return nil, err
+	}
+	f.global = *global
+	return f, nil
+}
+
+// parseSection reads the settings of a mapping node
+func parseSection(path string, node *yaml.Node, isGame bool) (*fileSection, error) {
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d: expected a mapping of settings", path, node.Line)
+	}
+	sec := &fileSection{values: map[string]string{}}
+	seen := make(map[string]bool, len(node.Content)/2)
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+		name := key.Value
+		if seen[name] {
+			return nil, fmt.Errorf("%s:%d: '%s' is set more than once", path, key.Line, name)
+		}
+		seen[name] = true
+		switch {
+		case name == "games" && !isGame:
+			continue
+		case name == "id" && isGame:
+			sec.id = strings.TrimSpace(val.Value)
+			continue
+		case name == "players":
+			if err := val.Decode(&sec.players); err != nil {
+				return nil, fmt.Errorf("%s:%d: invalid players: %w", path, val.Line, err)
+			}
+			continue
+		}
+
+		setting := strings.ToUpper(name)
+		if !isSettingKey(setting) || name != strings.ToLower(name) {
+			return nil, fmt.Errorf("%s:%d: unknown setting '%s'", path, key.Line, name)
+		}
+		switch val.Kind {
+		case yaml.ScalarNode:
+			sec.values[setting] = val.Value
+		case yaml.SequenceNode:
+			items := make([]string, 0, len(val.Content))
+			for _, item := range val.Content {
+				if item.Kind != yaml.ScalarNode {
+					return nil, fmt.Errorf("%s:%d: %s must be a list of values", path, item.Line, name)
+				}
+				items = append(items, item.Value)
+			}
+			sec.values[setting] = strings.Join(items, ",")
+		default:
+			return nil, fmt.Errorf("%s:%d: %s must be a value or a list", path, val.Line, name)
+		}
+	}
+	return sec, nil
+}
+
+// isSettingKey reports whether key names a known setting or the _FILE variant of a secret
+func isSettingKey(key string) bool {
+	for _, s := range Settings {
+		if s.Key == key || (s.Secret && s.Key+"_FILE" == key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,133 @@
+package types
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+)
+
+// LiveConfig shares a game's configuration with its running monitor. Reloads replace
+// the whole Config at once, so a monitor never sees a half-applied change.
+type LiveConfig struct {
+	cfg     atomic.Pointer[Config]
+	version atomic.Uint64
+}
+
+// NewLiveConfig returns a LiveConfig holding cfg.
+func NewLiveConfig(cfg Config) *LiveConfig {
+	l := &LiveConfig{}
+	l.cfg.Store(&cfg)
+	return l
+}
+
+// Get returns the current configuration.
+func (l *LiveConfig) Get() Config {
+	return *l.cfg.Load()
+}
+
+// Set replaces the configuration.
+func (l *LiveConfig) Set(cfg Config) {
+	l.cfg.Store(&cfg)
+	l.version.Add(1)
+}
+
+// Version increases with every Set, so monitors can cheaply notice changes.
+func (l *LiveConfig) Version() uint64 {
+	return l.version.Load()
+}
+
+// restartKeys are settings that only take effect when the bot restarts
+var restartKeys = map[string]bool{
+	"GAME_NAME":            true,
+	"GAME_PROFILE":         true,
+	"WATCH_DIRECTORY":      true,
+	"HTTP_ADDR":            true,
+	"WATCH_MODE":           true,
+	"SYNCTHING_URL":        true,
+	"SYNCTHING_API_KEY":    true,
+	"SYNCTHING_FOLDER":     true,
+	"S3_ENDPOINT":          true,
+	"S3_REGION":            true,
+	"S3_ACCESS_KEY_ID":     true,
+	"S3_SECRET_ACCESS_KEY": true,
+	"WEBDAV_USERNAME":      true,
+	"WEBDAV_PASSWORD":      true,
+}
+
+// Change describes one setting that differs between two configurations.
+type Change struct {
+	Key     string
+	Old     string
+	New     string
+	Secret  bool
+	Restart bool // the change only takes effect after a restart
+}
+
+// String formats the change for logs and admin alerts, hiding secret values.
+func (c Change) String() string {
+	s := fmt.Sprintf("%s: %s → %s", c.Key, orUnset(c.Old), orUnset(c.New))
+	if c.Secret {
+		s = c.Key + ": changed"
+	}
+	if c.Restart {
+		s += " (takes effect after a restart)"
+	}
+	return s
+}
+
+// Reload merges a reloaded configuration into the running one. Safe changes are taken
+// from updated, while settings that need a restart keep their running values. It
+// returns the merged configuration and every change found.
+func Reload(running, updated Config) (Config, []Change) {
+	var changes []Change
+	for _, s := range Settings {
+		old, cur := running.Setting(s.Key), updated.Setting(s.Key)
+		if old != cur {
+			changes = append(changes, Change{Key: s.Key, Old: old, New: cur, Secret: s.Secret, Restart: restartKeys[s.Key]})
+		}
+	}
+	if !reflect.DeepEqual(running.Players, updated.Players) {
+		changes = append(changes, Change{
+			Key: "players",
+			Old: fmt.Sprintf("%d players", len(running.Players)),
+			New: fmt.Sprintf("%d players", len(updated.Players)),
+		})
+	}
+
+	// Keep what the running monitor was started with
+	merged := updated
+	merged.ID = running.ID
+	merged.GameName = running.GameName
+	merged.GameProfile = running.GameProfile
+	merged.WatchDirectory = running.WatchDirectory
+	merged.HTTPAddr = running.HTTPAddr
+	merged.WatchMode = running.WatchMode
+	merged.SyncthingURL = running.SyncthingURL
+	merged.SyncthingAPIKey = running.SyncthingAPIKey
+	merged.SyncthingFolder = running.SyncthingFolder
+	merged.S3Endpoint = running.S3Endpoint
+	merged.S3Region = running.S3Region
+	merged.S3AccessKeyID = running.S3AccessKeyID
+	merged.S3SecretAccessKey = running.S3SecretAccessKey
+	merged.WebDAVUsername = running.WebDAVUsername
+	merged.WebDAVPassword = running.WebDAVPassword
+	merged.ForeignGameNames = running.ForeignGameNames
+	merged.Sources = make(map[string]Source, len(updated.Sources))
+	for key, src := range updated.Sources {
+		if restartKeys[key] {
+			src = running.Sources[key]
+		}
+		merged.Sources[key] = src
+	}
+	if len(parseExtensions(merged.AllowedExtensionsRaw)) == 0 {
+		merged.AllowedExtensions = merged.Profile().Extensions
+	}
+	return merged, changes
+}
+
+func orUnset(s string) string {
+	if s == "" {
+		return "(not set)"
+	}
+	return s
+}
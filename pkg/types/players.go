@@ -0,0 +1,39 @@
+package types
+
+import (
+	"fmt"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+)
+
+// RosterPath returns the configured roster file, or a players.yaml found in a local watch directory.
+func (c Config) RosterPath() string {
+	if c.RosterFile != "" || store.IsRemote(c.WatchDirectory) {
+		return c.RosterFile
+	}
+	return userparser.FindRosterFile(c.WatchDirectory)
+}
+
+// LoadPlayers loads the players from the roster file, the config file's players or USER_MAPPINGS.
+// A roster file found in the watch directory that cannot be loaded is skipped in favour of
+// players configured directly; skipped then explains why.
+func (c Config) LoadPlayers() (users []userparser.UserMapping, skipped error, err error) {
+	rosterPath := c.RosterPath()
+	users, err = userparser.LoadUsers(rosterPath, c.Players, c.UserMappingsRaw)
+	if err != nil && rosterPath != "" && c.RosterFile == "" && (c.UserMappingsRaw != "" || len(c.Players) > 0) {
+		skipped = fmt.Errorf("invalid roster file %s: %w", rosterPath, err)
+		users, err = userparser.LoadUsers("", c.Players, c.UserMappingsRaw)
+	}
+	if err != nil {
+		switch {
+		case rosterPath != "" && skipped == nil:
+			return nil, nil, fmt.Errorf("failed to load roster file %s: %w", rosterPath, err)
+		case len(c.Players) > 0:
+			return nil, skipped, fmt.Errorf("failed to load players from the config file: %w", err)
+		default:
+			return nil, skipped, fmt.Errorf("failed to parse USER_MAPPINGS: %w. Please check the format (e.g., '1 User1 ID1,2 User2 ID2')", err)
+		}
+	}
+	return users, skipped, nil
+}
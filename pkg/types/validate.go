@@ -0,0 +1,201 @@
+package types
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/store"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/userparser"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/watcher"
+)
+
+// Problem is a single configuration issue found by Validate.
+type Problem struct {
+	Key     string // setting the problem relates to
+	Message string
+	Fatal   bool // the bot cannot run with this problem
+}
+
+// ValidationError collects every problem found in one game's configuration.
+type ValidationError struct {
+	Label    string
+	Problems []Problem
+}
+
+// Error lists all problems, one per line.
+func (e *ValidationError) Error() string {
+	lines := make([]string, 0, len(e.Problems)+1)
+	lines = append(lines, fmt.Sprintf("%sfound %d configuration problem(s):", e.Label, len(e.Problems)))
+	for _, p := range e.Problems {
+		lines = append(lines, "  - "+p.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Fatal reports whether any problem prevents the bot from starting.
+func (e *ValidationError) Fatal() bool {
+	for _, p := range e.Problems {
+		if p.Fatal {
+			return true
+		}
+	}
+	return false
+}
+
+// String formats the problem as "KEY: message".
+func (p Problem) String() string {
+	if p.Key == "" {
+		return p.Message
+	}
+	return p.Key + ": " + p.Message
+}
+
+// Validate checks the configuration and returns a *ValidationError listing every problem,
+// or nil if there are none. Problems that are not fatal are warnings.
+func (c Config) Validate() error {
+	problems := append([]Problem(nil), c.problems...)
+	add := func(key string, fatal bool, format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if src, ok := c.Sources[key]; ok && !src.IsDefault() && src.Kind != SourceInput {
+			msg += " (from " + src.String() + ")"
+		}
+		problems = append(problems, Problem{Key: key, Message: msg, Fatal: fatal})
+	}
+
+	// Players, loaded the same way the monitor loads them
+	rosterPath := c.RosterPath()
+	if rosterPath == "" && c.UserMappingsRaw == "" && len(c.Players) == 0 {
+		add("USER_MAPPINGS", true, "no players configured; set USER_MAPPINGS or ROSTER_FILE, list players in the config file, or add players.yaml to the watch directory")
+	} else if _, skipped, err := c.LoadPlayers(); err != nil {
+		key := "USER_MAPPINGS"
+		switch {
+		case rosterPath != "" && skipped == nil:
+			key = "ROSTER_FILE"
+		case len(c.Players) > 0:
+			key = "players"
+		}
+		add(key, true, "%v", err)
+	} else if skipped != nil {
+		add("ROSTER_FILE", false, "ignoring %v; using the configured players", skipped)
+	}
+	if c.RosterFile != "" && c.UserMappingsRaw != "" {
+		add("USER_MAPPINGS", false, "ignored because ROSTER_FILE is set")
+	}
+
+	// Watch directory; remote stores are checked when the monitor connects
+	if store.IsRemote(c.WatchDirectory) {
+		if _, err := store.Open(c.WatchDirectory, c.StoreOptions()); err != nil {
+			add("WATCH_DIRECTORY", true, "%v", err)
+		}
+	} else if info, err := os.Stat(c.WatchDirectory); err != nil {
+		add("WATCH_DIRECTORY", true, "directory '%s' is not accessible: %v", c.WatchDirectory, err)
+	} else if !info.IsDir() {
+		add("WATCH_DIRECTORY", true, "'%s' is not a directory", c.WatchDirectory)
+	} else if _, err := os.ReadDir(c.WatchDirectory); err != nil {
+		add("WATCH_DIRECTORY", true, "directory '%s' cannot be read: %v", c.WatchDirectory, err)
+	}
+
+	// Webhooks and APIs
+	if c.WebhookURL == "" {
+		add("DISCORD_WEBHOOK_URL", true, "not set; turn notifications cannot be delivered")
+	} else if err := checkURL(c.WebhookURL); err != nil {
+		add("DISCORD_WEBHOOK_URL", true, "malformed webhook URL: %v", err)
+	}
+	if c.AdminWebhookURL != "" {
+		if err := checkURL(c.AdminWebhookURL); err != nil {
+			add("ADMIN_WEBHOOK_URL", true, "malformed webhook URL: %v", err)
+		}
+	}
+	if c.DiscordAPIURL != "" {
+		if err := checkURL(c.DiscordAPIURL); err != nil {
+			add("DISCORD_API_URL", true, "malformed URL: %v", err)
+		}
+	}
+	if c.DiscordGuildID != "" {
+		if err := userparser.ValidateSnowflake(c.DiscordGuildID); err != nil {
+			add("DISCORD_GUILD_ID", true, "invalid server ID '%s': %v", c.DiscordGuildID, err)
+		} else if c.DiscordBotToken == "" {
+			add("DISCORD_GUILD_ID", false, "ignored because DISCORD_BOT_TOKEN is not set")
+		}
+	}
+	if c.SyncthingURL != "" {
+		if err := checkURL(c.SyncthingURL); err != nil {
+			add("SYNCTHING_URL", true, "malformed URL: %v", err)
+		}
+	}
+	if c.SyncthingAPIKey == "" {
+		for _, key := range []string{"SYNCTHING_URL", "SYNCTHING_FOLDER"} {
+			if c.Setting(key) != "" {
+				add(key, false, "ignored because SYNCTHING_API_KEY is not set")
+			}
+		}
+	}
+	if c.HTTPAddr != "" {
+		if _, _, err := net.SplitHostPort(c.HTTPAddr); err != nil {
+			add("HTTP_ADDR", true, "invalid address '%s', expected host:port or :port", c.HTTPAddr)
+		}
+	}
+
+	// Game handling
+	if p, ok := game.Lookup(c.GameProfile); !ok {
+		add("GAME_PROFILE", true, "unknown profile '%s' (available: %s)", c.GameProfile, strings.Join(game.Names(), ", "))
+	} else if len(p.Extensions) == 0 && strings.TrimSpace(c.AllowedExtensionsRaw) == "" {
+		add("ALLOWED_EXTENSIONS", true, "required with GAME_PROFILE=%s, which does not know the game's save extensions", p.ID)
+	}
+	if strings.TrimSpace(c.GameName) == "" {
+		add("GAME_NAME", true, "must not be empty")
+	}
+	switch c.AutoRename {
+	case AutoRenameOff, AutoRenameCopy, AutoRenameRename:
+	default:
+		add("AUTO_RENAME", true, "unknown mode '%s' (expected off, copy or rename)", c.AutoRename)
+	}
+	if strings.TrimSpace(c.AllowedExtensionsRaw) != "" && len(parseExtensions(c.AllowedExtensionsRaw)) == 0 {
+		add("ALLOWED_EXTENSIONS", true, "no extensions listed; leave it unset to use the profile's extensions")
+	}
+
+	// Timing
+	if c.FileDebounce < 0 {
+		add("FILE_DEBOUNCE_MS", true, "must not be negative, got %s", duration.Short(c.FileDebounce))
+	}
+	if c.ReminderInterval <= 0 {
+		add("REMINDER_INTERVAL_MINUTES", true, "must be positive, got %s", duration.Short(c.ReminderInterval))
+	}
+	if c.PollInterval <= 0 {
+		add("POLL_INTERVAL_SEC", true, "must be positive, got %s", duration.Short(c.PollInterval))
+	}
+	if c.RescanInterval <= 0 {
+		add("RESCAN_INTERVAL", true, "must be positive, got %s", duration.Short(c.RescanInterval))
+	}
+
+	// File watching
+	if !slices.Contains(watcher.Modes, c.WatchMode) {
+		add("WATCH_MODE", true, "unknown mode '%s' (expected %s)", c.WatchMode, strings.Join(watcher.Modes, ", "))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+	return &ValidationError{Label: c.Label(), Problems: problems}
+}
+
+// checkURL verifies that s is an absolute http(s) URL
+func checkURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("cannot be parsed")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("expected an http or https URL")
+	}
+	if u.Host == "" {
+		return fmt.Errorf("missing host")
+	}
+	return nil
+}
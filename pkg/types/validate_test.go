@@ -0,0 +1,116 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// loadTestGame loads a single game from settings given as environment variables
+func loadTestGame(t *testing.T, env map[string]string) Config {
+	t.Helper()
+	t.Setenv("WATCH_DIRECTORY", t.TempDir())
+	t.Setenv("DISCORD_WEBHOOK_URL", "https://discord.com/api/webhooks/1/token")
+	for k, v := range env {
+		t.Setenv(k, v)
+	}
+	games, err := Load(LoadOptions{})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	return games[0]
+}
+
+// problemsFor returns the problems Validate reports for key
+func problemsFor(t *testing.T, c Config, key string) []Problem {
+	t.Helper()
+	err := c.Validate()
+	if err == nil {
+		return nil
+	}
+	verr, ok := err.(*ValidationError)
+	if !ok {
+		t.Fatalf("Validate returned %T, want *ValidationError", err)
+	}
+	var out []Problem
+	for _, p := range verr.Problems {
+		if p.Key == key {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func TestValidateLoadsPlayers(t *testing.T) {
+	c := loadTestGame(t, map[string]string{"USER_MAPPINGS": "1 Alice 123456789012345678,2 Bob 223456789012345678"})
+	if err := c.Validate(); err != nil {
+		t.Fatalf("Validate with valid players: %v", err)
+	}
+
+	c = loadTestGame(t, map[string]string{"USER_MAPPINGS": "1 Alice 123,2 Bob 223456789012345678"})
+	problems := problemsFor(t, c, "USER_MAPPINGS")
+	if len(problems) != 1 || !problems[0].Fatal || !strings.Contains(problems[0].Message, "123") {
+		t.Errorf("Validate with an invalid Discord ID: problems = %v, want one fatal USER_MAPPINGS problem", problems)
+	}
+
+	c = loadTestGame(t, map[string]string{"ROSTER_FILE": filepath.Join(t.TempDir(), "missing.yaml")})
+	if problems := problemsFor(t, c, "ROSTER_FILE"); len(problems) != 1 || !problems[0].Fatal {
+		t.Errorf("Validate with a missing roster file: problems = %v, want one fatal ROSTER_FILE problem", problems)
+	}
+}
+
+func TestValidateSkipsInvalidFoundRoster(t *testing.T) {
+	c := loadTestGame(t, map[string]string{"USER_MAPPINGS": "1 Alice 123456789012345678,2 Bob 223456789012345678"})
+	if err := os.WriteFile(filepath.Join(c.WatchDirectory, "players.yaml"), []byte("players: [oops"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	problems := problemsFor(t, c, "ROSTER_FILE")
+	if len(problems) != 1 || problems[0].Fatal {
+		t.Errorf("Validate with an invalid players.yaml and USER_MAPPINGS: problems = %v, want one warning", problems)
+	}
+	if users, skipped, err := c.LoadPlayers(); err != nil || skipped == nil || len(users) != 2 {
+		t.Errorf("LoadPlayers = %d users, skipped %v, err %v; want the USER_MAPPINGS players", len(users), skipped, err)
+	}
+}
+
+func TestAllowedExtensionsDot(t *testing.T) {
+	c := loadTestGame(t, map[string]string{
+		"USER_MAPPINGS":      "1 Alice 123456789012345678",
+		"ALLOWED_EXTENSIONS": ".SE1, sav",
+	})
+	if !slices.Equal(c.AllowedExtensions, []string{"se1", "sav"}) {
+		t.Errorf("AllowedExtensions = %v, want [se1 sav]", c.AllowedExtensions)
+	}
+	if problems := problemsFor(t, c, "ALLOWED_EXTENSIONS"); len(problems) != 0 {
+		t.Errorf("Validate reported %v for extensions with a dot", problems)
+	}
+
+	c = loadTestGame(t, map[string]string{
+		"USER_MAPPINGS":      "1 Alice 123456789012345678",
+		"ALLOWED_EXTENSIONS": " . ,",
+	})
+	if problems := problemsFor(t, c, "ALLOWED_EXTENSIONS"); len(problems) != 1 || !problems[0].Fatal {
+		t.Errorf("Validate with no extensions: problems = %v, want one fatal problem", problems)
+	}
+}
+
+func TestGenericProfileNeedsExtensions(t *testing.T) {
+	c := loadTestGame(t, map[string]string{
+		"USER_MAPPINGS": "1 Alice 123456789012345678",
+		"GAME_PROFILE":  "generic",
+	})
+	if problems := problemsFor(t, c, "ALLOWED_EXTENSIONS"); len(problems) != 1 || !problems[0].Fatal {
+		t.Errorf("Validate with the generic profile and no extensions: problems = %v, want one fatal problem", problems)
+	}
+
+	c = loadTestGame(t, map[string]string{
+		"USER_MAPPINGS":      "1 Alice 123456789012345678",
+		"GAME_PROFILE":       "generic",
+		"ALLOWED_EXTENSIONS": "sav",
+	})
+	if problems := problemsFor(t, c, "ALLOWED_EXTENSIONS"); len(problems) != 0 {
+		t.Errorf("Validate with the generic profile and extensions reported %v", problems)
+	}
+}
@@ -0,0 +1,97 @@
+package userparser
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+)
+
+// RosterFileNames are the roster file names looked up in the watch directory
+// when no ROSTER_FILE is configured, in order of preference.
+var RosterFileNames = []string{"players.yaml", "players.yml", "players.json"}
+
+// FindRosterFile returns the path of the first roster file found in dir, or "" if none exists.
+func FindRosterFile(dir string) string {
+	for _, name := range RosterFileNames {
+		p := filepath.Join(dir, name)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+	}
+	return ""
+}
+
+// DiffMappings describes the differences between two rosters as human readable lines.
+// Players are matched by folded username; an empty result means the rosters are equivalent.
+func DiffMappings(old, updated []UserMapping) []string {
+	var changes []string
+
+	oldByName := make(map[string]UserMapping, len(old))
+	for _, m := range old {
+		oldByName[FoldName(m.Username)] = m
+	}
+	newByName := make(map[string]UserMapping, len(updated))
+	for _, m := range updated {
+		newByName[FoldName(m.Username)] = m
+	}
+
+	for _, m := range updated {
+		prev, ok := oldByName[FoldName(m.Username)]
+		if !ok {
+			changes = append(changes, fmt.Sprintf("➕ %s joined at position %d", m.Username, m.Order))
+			continue
+		}
+		changes = append(changes, diffPlayer(prev, m)...)
+	}
+	for _, m := range old {
+		if _, ok := newByName[FoldName(m.Username)]; !ok {
+			changes = append(changes, fmt.Sprintf("➖ %s was removed", m.Username))
+		}
+	}
+	return changes
+}
+
+func diffPlayer(prev, cur UserMapping) []string {
+	var changes []string
+	field := func(name, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("✏️ %s: %s changed from %s to %s", cur.Username, name, orNone(from), orNone(to)))
+		}
+	}
+
+	field("order", fmt.Sprint(prev.Order), fmt.Sprint(cur.Order))
+	if prev.DiscordID != cur.DiscordID {
+		changes = append(changes, fmt.Sprintf("✏️ %s: Discord ID updated", cur.Username))
+	}
+	field("aliases", strings.Join(prev.Aliases, ", "), strings.Join(cur.Aliases, ", "))
+	field("timezone", prev.TimeZone, cur.TimeZone)
+	field("quiet hours", prev.QuietHours.String(), cur.QuietHours.String())
+	field("reminders", formatReminderPolicy(prev.Reminders), formatReminderPolicy(cur.Reminders))
+	field("notify", strings.Join(prev.Channels, ", "), strings.Join(cur.Channels, ", "))
+	field("admin", fmt.Sprint(prev.Admin), fmt.Sprint(cur.Admin))
+	return changes
+}
+
+func formatReminderPolicy(p ReminderPolicy) string {
+	if p.Disabled {
+		return "disabled"
+	}
+	var parts []string
+	if interval, err := p.IntervalDuration(); err == nil && interval > 0 {
+		parts = append(parts, "every "+duration.Format(interval))
+	}
+	if p.Max > 0 {
+		parts = append(parts, fmt.Sprintf("max %d", p.Max))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
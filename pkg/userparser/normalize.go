@@ -0,0 +1,61 @@
+package userparser
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// transliterations maps characters that have a conventional ASCII spelling
+// (e.g. German umlauts) to that spelling, so "Jörg" can also match "Joerg".
+var transliterations = strings.NewReplacer(
+	"ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss",
+	"æ", "ae", "ø", "oe", "å", "aa", "œ", "oe",
+)
+
+// stripMarks applies NFKD decomposition and drops combining marks, turning
+// e.g. "Jörg" into "jorg" and full-width or ligature characters into ASCII.
+func stripMarks(s string) string {
+	var b strings.Builder
+	for _, r := range norm.NFKD.String(s) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FoldName returns the primary comparison form of a name: lowercased, trimmed,
+// NFKD-folded and with diacritics removed.
+func FoldName(s string) string {
+	return stripMarks(strings.ToLower(norm.NFC.String(strings.TrimSpace(s))))
+}
+
+// FoldVariants returns every comparison form of s. Besides FoldName it
+// includes the transliterated spelling (ö -> oe) and, for names containing
+// spaces, an underscore-joined spelling as commonly used in filenames.
+func FoldVariants(s string) []string {
+	lower := strings.ToLower(norm.NFC.String(strings.TrimSpace(s)))
+	candidates := []string{
+		stripMarks(lower),
+		stripMarks(transliterations.Replace(lower)),
+	}
+
+	seen := make(map[string]bool, len(candidates)*2)
+	out := make([]string, 0, len(candidates)*2)
+	add := func(v string) {
+		if v != "" && !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	for _, c := range candidates {
+		add(c)
+		if strings.Contains(c, " ") {
+			add(strings.ReplaceAll(c, " ", "_"))
+		}
+	}
+	return out
+}
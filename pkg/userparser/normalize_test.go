@@ -0,0 +1,44 @@
+package userparser
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFoldName(t *testing.T) {
+	tests := map[string]string{
+		"  Alice ":    "alice",
+		"Jörg":        "jorg",
+		"Jo\u0308rg":  "jorg",  // decomposed umlaut
+		"ＡＬＩＣＥ":       "alice", // full-width
+		"ﬁnn":         "finn",  // ligature
+		"Zoë Saldaña": "zoe saldana",
+		"Ærøskøbing":  "ærøskøbing",
+		"Δημήτρης":    "δημητρης",
+	}
+	for in, want := range tests {
+		if got := FoldName(in); got != want {
+			t.Errorf("FoldName(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestFoldVariants(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Alice", []string{"alice"}},
+		{"Jörg", []string{"jorg", "joerg"}},
+		{"Jo\u0308rg", []string{"jorg", "joerg"}},
+		{"Straße", []string{"straße", "strasse"}},
+		{"Bjørn", []string{"bjørn", "bjoern"}},
+		{"Jörg Müller", []string{"jorg muller", "jorg_muller", "joerg mueller", "joerg_mueller"}},
+		{"  ", []string{}},
+	}
+	for _, tt := range tests {
+		if got := FoldVariants(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("FoldVariants(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,218 @@
+package userparser
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"gopkg.in/yaml.v3"
+)
+
+// knownChannels lists the notification channels a player can opt into.
+var knownChannels = map[string]bool{
+	"discord": true,
+}
+
+// Roster is the top-level structure of a YAML or JSON roster file.
+type Roster struct {
+	Players []Player `yaml:"players" json:"players"`
+}
+
+// Player is a single roster entry with its per-player attributes.
+type Player struct {
+	Order      int            `yaml:"order" json:"order"`
+	Name       string         `yaml:"name" json:"name"`
+	DiscordID  string         `yaml:"discord_id" json:"discord_id"`
+	Aliases    []string       `yaml:"aliases,omitempty" json:"aliases,omitempty"`
+	TimeZone   string         `yaml:"timezone,omitempty" json:"timezone,omitempty"`
+	QuietHours string         `yaml:"quiet_hours,omitempty" json:"quiet_hours,omitempty"`
+	Reminders  ReminderPolicy `yaml:"reminders,omitempty" json:"reminders,omitempty"`
+	Notify     []string       `yaml:"notify,omitempty" json:"notify,omitempty"`
+	Admin      bool           `yaml:"admin,omitempty" json:"admin,omitempty"`
+}
+
+// ReminderPolicy controls how a player is reminded while it is their turn.
+// Zero values fall back to the global reminder settings.
+type ReminderPolicy struct {
+	Disabled bool `yaml:"disabled,omitempty" json:"disabled,omitempty"`
+	// Interval is a duration such as "6h"; IntervalMinutes is the legacy form
+	Interval        string `yaml:"interval,omitempty" json:"interval,omitempty"`
+	IntervalMinutes int    `yaml:"interval_minutes,omitempty" json:"interval_minutes,omitempty"`
+	Max             int    `yaml:"max,omitempty" json:"max,omitempty"`
+}
+
+// IntervalDuration returns the player's reminder interval, or 0 to use the global one.
+func (p ReminderPolicy) IntervalDuration() (time.Duration, error) {
+	if strings.TrimSpace(p.Interval) != "" {
+		return duration.Parse(p.Interval, time.Minute)
+	}
+	return time.Duration(p.IntervalMinutes) * time.Minute, nil
+}
+
+// QuietHours is a daily window, in minutes since midnight, during which a player
+// should not be reminded. The window may wrap past midnight.
+type QuietHours struct {
+	Start int
+	End   int
+}
+
+// IsZero reports whether no quiet hours are configured.
+func (q QuietHours) IsZero() bool { return q.Start == q.End }
+
+// Contains reports whether the wall-clock time of t falls inside the window.
+func (q QuietHours) Contains(t time.Time) bool {
+	if q.IsZero() {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	if q.Start < q.End {
+		return m >= q.Start && m < q.End
+	}
+	return m >= q.Start || m < q.End
+}
+
+// String formats the window as "HH:MM-HH:MM".
+func (q QuietHours) String() string {
+	if q.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%02d:%02d-%02d:%02d", q.Start/60, q.Start%60, q.End/60, q.End%60)
+}
+
+// ParseQuietHours parses a window such as "22:00-07:30".
+func ParseQuietHours(s string) (QuietHours, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return QuietHours{}, nil
+	}
+	from, to, ok := strings.Cut(s, "-")
+	if !ok {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours '%s': expected 'HH:MM-HH:MM'", s)
+	}
+	start, err := time.Parse("15:04", strings.TrimSpace(from))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours start '%s': expected HH:MM", from)
+	}
+	end, err := time.Parse("15:04", strings.TrimSpace(to))
+	if err != nil {
+		return QuietHours{}, fmt.Errorf("invalid quiet hours end '%s': expected HH:MM", to)
+	}
+	return QuietHours{
+		Start: start.Hour()*60 + start.Minute(),
+		End:   end.Hour()*60 + end.Minute(),
+	}, nil
+}
+
+// LoadUsers returns the player mappings from rosterFile when set, then from players
+// given inline in the config file, otherwise from the USER_MAPPINGS shorthand in raw.
+func LoadUsers(rosterFile string, players []Player, raw string) ([]UserMapping, error) {
+	if strings.TrimSpace(rosterFile) != "" {
+		return ParseRosterFile(rosterFile)
+	}
+	if len(players) > 0 {
+		return PlayersToMappings(players)
+	}
+	return ParseUsersFromString(raw)
+}
+
+// ParseRosterFile reads a roster file. Files ending in .json are parsed as JSON,
+// anything else as YAML.
+func ParseRosterFile(path string) ([]UserMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading roster file: %w", err)
+	}
+	return ParseRoster(data, strings.EqualFold(filepath.Ext(path), ".json"))
+}
+
+// ParseRoster parses roster file content into validated user mappings.
+func ParseRoster(data []byte, isJSON bool) ([]UserMapping, error) {
+	var roster Roster
+	if isJSON {
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&roster); err != nil {
+			return nil, fmt.Errorf("invalid roster JSON: %w", err)
+		}
+	} else {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		if err := dec.Decode(&roster); err != nil {
+			return nil, fmt.Errorf("invalid roster YAML: %w", err)
+		}
+	}
+	return PlayersToMappings(roster.Players)
+}
+
+// PlayersToMappings validates roster entries and converts them into user mappings.
+func PlayersToMappings(players []Player) ([]UserMapping, error) {
+	var userMappings []UserMapping
+	for i, p := range players {
+		m, err := p.toMapping()
+		if err != nil {
+			return nil, fmt.Errorf("invalid player %d (%s): %w", i+1, p.Name, err)
+		}
+		userMappings = append(userMappings, m)
+	}
+	return finalizeMappings(userMappings)
+}
+
+func (p Player) toMapping() (UserMapping, error) {
+	name := strings.TrimSpace(p.Name)
+	if name == "" {
+		return UserMapping{}, fmt.Errorf("name is empty")
+	}
+	if strings.TrimSpace(p.DiscordID) == "" {
+		return UserMapping{}, fmt.Errorf("discord_id is empty")
+	}
+	if p.Order == 0 {
+		return UserMapping{}, fmt.Errorf("order is missing")
+	}
+
+	m := UserMapping{
+		Order:     p.Order,
+		Username:  name,
+		DiscordID: strings.TrimSpace(p.DiscordID),
+		Aliases:   p.Aliases,
+		TimeZone:  p.TimeZone,
+		Reminders: p.Reminders,
+		Admin:     p.Admin,
+	}
+
+	if p.TimeZone != "" {
+		loc, err := time.LoadLocation(p.TimeZone)
+		if err != nil {
+			return UserMapping{}, fmt.Errorf("unknown timezone '%s'", p.TimeZone)
+		}
+		m.Location = loc
+	}
+
+	qh, err := ParseQuietHours(p.QuietHours)
+	if err != nil {
+		return UserMapping{}, err
+	}
+	m.QuietHours = qh
+
+	interval, err := p.Reminders.IntervalDuration()
+	if err != nil {
+		return UserMapping{}, fmt.Errorf("invalid reminder interval: %w", err)
+	}
+	if interval < 0 || p.Reminders.Max < 0 {
+		return UserMapping{}, fmt.Errorf("reminder interval and max must not be negative")
+	}
+
+	for _, c := range p.Notify {
+		c = strings.ToLower(strings.TrimSpace(c))
+		if !knownChannels[c] {
+			return UserMapping{}, fmt.Errorf("unknown notification channel '%s'", c)
+		}
+		m.Channels = append(m.Channels, c)
+	}
+
+	return m, nil
+}
@@ -0,0 +1,254 @@
+package userparser
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuietHours(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    QuietHours
+		wantErr string
+	}{
+		{"22:00-07:30", QuietHours{Start: 22 * 60, End: 7*60 + 30}, ""},
+		{"09:15-17:45", QuietHours{Start: 9*60 + 15, End: 17*60 + 45}, ""},
+		{" 23:00 - 06:00 ", QuietHours{Start: 23 * 60, End: 6 * 60}, ""},
+		{"", QuietHours{}, ""},
+		{"22:00", QuietHours{}, "expected 'HH:MM-HH:MM'"},
+		{"10pm-07:00", QuietHours{}, "invalid quiet hours start"},
+		{"22:00-25:00", QuietHours{}, "invalid quiet hours end"},
+		{"22:00-7", QuietHours{}, "invalid quiet hours end"},
+	}
+	for _, tt := range tests {
+		got, err := ParseQuietHours(tt.in)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ParseQuietHours(%q) error = %v", tt.in, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ParseQuietHours(%q) error = %v, want %q", tt.in, err, tt.wantErr)
+		case got != tt.want:
+			t.Errorf("ParseQuietHours(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuietHoursContains(t *testing.T) {
+	at := func(hour, min int) time.Time { return time.Date(2026, 1, 10, hour, min, 0, 0, time.UTC) }
+	overnight := QuietHours{Start: 22 * 60, End: 7*60 + 30}
+	daytime := QuietHours{Start: 9 * 60, End: 17 * 60}
+	tests := []struct {
+		q    QuietHours
+		t    time.Time
+		want bool
+	}{
+		// Windows wrapping midnight cover both sides of it
+		{overnight, at(21, 59), false},
+		{overnight, at(22, 0), true},
+		{overnight, at(23, 59), true},
+		{overnight, at(0, 0), true},
+		{overnight, at(7, 29), true},
+		{overnight, at(7, 30), false},
+		{overnight, at(12, 0), false},
+		{daytime, at(8, 59), false},
+		{daytime, at(9, 0), true},
+		{daytime, at(16, 59), true},
+		{daytime, at(17, 0), false},
+		{daytime, at(23, 0), false},
+		{QuietHours{}, at(3, 0), false},
+	}
+	for _, tt := range tests {
+		if got := tt.q.Contains(tt.t); got != tt.want {
+			t.Errorf("%s Contains(%s) = %v, want %v", tt.q, tt.t.Format("15:04"), got, tt.want)
+		}
+	}
+
+	if got := overnight.String(); got != "22:00-07:30" {
+		t.Errorf("String() = %q, want 22:00-07:30", got)
+	}
+}
+
+func TestInQuietHoursUsesTimeZone(t *testing.T) {
+	users, err := PlayersToMappings([]Player{
+		{Order: 1, Name: "Alice", DiscordID: "123456789012345678", TimeZone: "Asia/Tokyo", QuietHours: "23:00-07:00"},
+	})
+	if err != nil {
+		t.Fatalf("PlayersToMappings: %v", err)
+	}
+	alice := users[0]
+	if alice.Location == nil || alice.Location.String() != "Asia/Tokyo" {
+		t.Fatalf("Location = %v, want Asia/Tokyo", alice.Location)
+	}
+
+	// 15:00 UTC is midnight in Tokyo, 03:00 UTC is noon
+	if !alice.InQuietHours(time.Date(2026, 1, 10, 15, 0, 0, 0, time.UTC)) {
+		t.Error("midnight in Tokyo should be quiet")
+	}
+	if alice.InQuietHours(time.Date(2026, 1, 10, 3, 0, 0, 0, time.UTC)) {
+		t.Error("noon in Tokyo should not be quiet")
+	}
+}
+
+func TestIntervalDuration(t *testing.T) {
+	tests := []struct {
+		policy  ReminderPolicy
+		want    time.Duration
+		wantErr bool
+	}{
+		{ReminderPolicy{}, 0, false},
+		{ReminderPolicy{Interval: "6h"}, 6 * time.Hour, false},
+		{ReminderPolicy{Interval: "1h30m"}, 90 * time.Minute, false},
+		{ReminderPolicy{Interval: "90"}, 90 * time.Minute, false},
+		{ReminderPolicy{IntervalMinutes: 45}, 45 * time.Minute, false},
+		// The duration string wins over the legacy minutes
+		{ReminderPolicy{Interval: "2h", IntervalMinutes: 45}, 2 * time.Hour, false},
+		{ReminderPolicy{Interval: "  ", IntervalMinutes: 45}, 45 * time.Minute, false},
+		{ReminderPolicy{Interval: "soon"}, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := tt.policy.IntervalDuration()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%+v IntervalDuration() error = %v, want error %v", tt.policy, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%+v IntervalDuration() = %s, want %s", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestParseRoster(t *testing.T) {
+	yamlRoster := `
+players:
+  - order: 2
+    name: Bob
+    discord_id: "223456789012345678"
+    reminders:
+      disabled: true
+  - order: 1
+    name: Alice
+    discord_id: "123456789012345678"
+    aliases: [Ally]
+    timezone: Europe/Berlin
+    quiet_hours: "22:00-07:00"
+    reminders:
+      interval: 6h
+      max: 3
+    notify: [Discord]
+    admin: true
+  - order: 3
+    name: Carol
+    discord_id: "323456789012345678"
+    reminders:
+      interval_minutes: 90
+`
+	users, err := ParseRoster([]byte(yamlRoster), false)
+	if err != nil {
+		t.Fatalf("ParseRoster: %v", err)
+	}
+	if len(users) != 3 || users[0].Username != "Alice" || users[1].Username != "Bob" || users[2].Username != "Carol" {
+		t.Fatalf("ParseRoster = %+v, want Alice, Bob, Carol in turn order", users)
+	}
+
+	alice := users[0]
+	if alice.QuietHours != (QuietHours{Start: 22 * 60, End: 7 * 60}) || alice.Location == nil || !alice.Admin {
+		t.Errorf("Alice = %+v, want quiet hours, a time zone and admin", alice)
+	}
+	if len(alice.Channels) != 1 || alice.Channels[0] != "discord" {
+		t.Errorf("Alice channels = %q, want [discord]", alice.Channels)
+	}
+
+	// Each player keeps their own reminder policy
+	if interval, _ := alice.Reminders.IntervalDuration(); interval != 6*time.Hour || alice.Reminders.Max != 3 {
+		t.Errorf("Alice reminders = %+v, want every 6h, max 3", alice.Reminders)
+	}
+	if !users[1].Reminders.Disabled {
+		t.Errorf("Bob reminders = %+v, want disabled", users[1].Reminders)
+	}
+	if interval, _ := users[2].Reminders.IntervalDuration(); interval != 90*time.Minute || users[2].Reminders.Max != 0 {
+		t.Errorf("Carol reminders = %+v, want every 90m with the global max", users[2].Reminders)
+	}
+
+	jsonRoster := `{"players": [
+		{"order": 1, "name": "Alice", "discord_id": "123456789012345678", "reminders": {"interval": "12h"}},
+		{"order": 2, "name": "Bob", "discord_id": "223456789012345678"}
+	]}`
+	users, err = ParseRoster([]byte(jsonRoster), true)
+	if err != nil {
+		t.Fatalf("ParseRoster JSON: %v", err)
+	}
+	if interval, _ := users[0].Reminders.IntervalDuration(); interval != 12*time.Hour {
+		t.Errorf("JSON Alice interval = %s, want 12h", interval)
+	}
+}
+
+func TestParseRosterErrors(t *testing.T) {
+	const bob = "  - order: 2\n    name: Bob\n    discord_id: \"223456789012345678\"\n"
+	tests := []struct {
+		name    string
+		data    string
+		isJSON  bool
+		wantErr string
+	}{
+		{"unknown player field", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    timezon: UTC\n" + bob, false, "field timezon not found"},
+		{"unknown top-level field", "player:\n  - order: 1\n", false, "field player not found"},
+		{"unknown reminder field", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    reminders:\n      every: 6h\n" + bob, false, "field every not found"},
+		{"unknown JSON field", `{"players": [{"order": 1, "name": "Alice", "discord_id": "123456789012345678", "admn": true}]}`, true, `unknown field "admn"`},
+		{"invalid time zone", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    timezone: Mars/Olympus\n" + bob, false, "unknown timezone 'Mars/Olympus'"},
+		{"invalid quiet hours", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    quiet_hours: late\n" + bob, false, "invalid quiet hours 'late'"},
+		{"invalid interval", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    reminders:\n      interval: soon\n" + bob, false, "invalid reminder interval"},
+		{"negative max", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    reminders:\n      max: -1\n" + bob, false, "must not be negative"},
+		{"unknown channel", "players:\n  - order: 1\n    name: Alice\n    discord_id: \"123456789012345678\"\n    notify: [email]\n" + bob, false, "unknown notification channel 'email'"},
+		{"missing order", "players:\n  - name: Alice\n    discord_id: \"123456789012345678\"\n" + bob, false, "order is missing"},
+		{"missing discord id", "players:\n  - order: 1\n    name: Alice\n" + bob, false, "discord_id is empty"},
+	}
+	for _, tt := range tests {
+		_, err := ParseRoster([]byte(tt.data), tt.isJSON)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadUsers(t *testing.T) {
+	dir := t.TempDir()
+	rosterFile := filepath.Join(dir, "players.json")
+	if err := os.WriteFile(rosterFile, []byte(`{"players": [
+		{"order": 1, "name": "Rosa", "discord_id": "123456789012345678"},
+		{"order": 2, "name": "Ravi", "discord_id": "223456789012345678"}
+	]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	players := []Player{
+		{Order: 1, Name: "Inline", DiscordID: "323456789012345678"},
+		{Order: 2, Name: "Other", DiscordID: "423456789012345678"},
+	}
+	const raw = "1 Env 523456789012345678, 2 Var 623456789012345678"
+
+	// The roster file wins over inline players, which win over USER_MAPPINGS
+	tests := []struct {
+		rosterFile string
+		players    []Player
+		want       string
+	}{
+		{rosterFile, players, "Rosa"},
+		{"", players, "Inline"},
+		{"", nil, "Env"},
+	}
+	for _, tt := range tests {
+		users, err := LoadUsers(tt.rosterFile, tt.players, raw)
+		if err != nil {
+			t.Fatalf("LoadUsers(%q): %v", tt.rosterFile, err)
+		}
+		if users[0].Username != tt.want {
+			t.Errorf("LoadUsers(%q) first player = %s, want %s", tt.rosterFile, users[0].Username, tt.want)
+		}
+	}
+
+	if _, err := LoadUsers(filepath.Join(dir, "missing.yaml"), players, raw); err == nil || !strings.Contains(err.Error(), "reading roster file") {
+		t.Errorf("missing roster file error = %v, want a read error", err)
+	}
+}
@@ -0,0 +1,40 @@
+package userparser
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// discordEpoch is the first millisecond of 2015, the epoch of Discord snowflakes.
+const discordEpoch = 1420070400000
+
+// SnowflakeTime returns the creation time embedded in a Discord snowflake ID.
+func SnowflakeTime(id string) (time.Time, error) {
+	n, err := strconv.ParseUint(id, 10, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("not a numeric snowflake")
+	}
+	return time.UnixMilli(int64(n>>22) + discordEpoch), nil
+}
+
+// ValidateSnowflake checks that id looks like a real Discord user ID: 17 to 20 digits
+// with an embedded creation time between the Discord epoch and now.
+func ValidateSnowflake(id string) error {
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return fmt.Errorf("must contain only digits")
+		}
+	}
+	if len(id) < 17 || len(id) > 20 {
+		return fmt.Errorf("has %d digits, expected 17 to 20", len(id))
+	}
+	created, err := SnowflakeTime(id)
+	if err != nil {
+		return err
+	}
+	if created.After(time.Now().Add(time.Hour)) {
+		return fmt.Errorf("has a creation time in the future (%s)", created.Format(time.RFC3339))
+	}
+	return nil
+}
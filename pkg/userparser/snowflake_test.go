@@ -0,0 +1,48 @@
+package userparser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateSnowflake(t *testing.T) {
+	tests := []struct {
+		id      string
+		wantErr string
+	}{
+		{"123456789012345678", ""},
+		{"80351110224678912", ""},   // 17 digits
+		{"1234567890123456789", ""}, // 19 digits
+		{"", "has 0 digits"},
+		{"123", "has 3 digits"},
+		{"1234567890123456", "has 16 digits"},
+		{"123456789012345678901", "has 21 digits"},
+		{"12345678901234567a", "only digits"},
+		{"-123456789012345678", "only digits"},
+		{"１２３４５６７８９０１２３４５６７８", "only digits"}, // full-width digits
+		{"18446744073709551615", "in the future"},
+		{"99999999999999999999", "not a numeric snowflake"}, // overflows uint64
+	}
+	for _, tt := range tests {
+		err := ValidateSnowflake(tt.id)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("ValidateSnowflake(%q) = %v, want nil", tt.id, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("ValidateSnowflake(%q) = %v, want an error containing %q", tt.id, err, tt.wantErr)
+		}
+	}
+}
+
+func TestSnowflakeTime(t *testing.T) {
+	// Example from Discord's API reference
+	got, err := SnowflakeTime("175928847299117063")
+	if err != nil {
+		t.Fatalf("SnowflakeTime: %v", err)
+	}
+	want := time.Date(2016, 4, 30, 11, 18, 25, 796000000, time.UTC)
+	if !got.Equal(want) {
+		t.Errorf("SnowflakeTime = %s, want %s", got.UTC(), want)
+	}
+}
@@ -6,17 +6,97 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 )
 
-// UserMapping holds the order, username, and Discord ID for a user.
+// UserMapping holds the order, username, Discord ID and optional aliases for a user.
+// The remaining attributes are only populated from a roster file.
 type UserMapping struct {
 	Order     int
 	Username  string
 	DiscordID string
+	Aliases   []string
+
+	TimeZone   string
+	Location   *time.Location
+	QuietHours QuietHours
+	Reminders  ReminderPolicy
+	Channels   []string
+	Admin      bool
+
+	// DisplayName is the Discord display name, filled in when a bot token is configured.
+	DisplayName string
+}
+
+// Label returns the username, followed by the Discord display name when it is known and differs.
+func (u UserMapping) Label() string {
+	if u.DisplayName == "" || u.DisplayName == u.Username {
+		return u.Username
+	}
+	return fmt.Sprintf("%s (%s)", u.Username, u.DisplayName)
+}
+
+// LocalTime returns t in the player's time zone, or unchanged if none is set.
+func (u UserMapping) LocalTime(t time.Time) time.Time {
+	if u.Location == nil {
+		return t
+	}
+	return t.In(u.Location)
+}
+
+// InQuietHours reports whether t falls within the player's quiet hours.
+func (u UserMapping) InQuietHours(t time.Time) bool {
+	return u.QuietHours.Contains(u.LocalTime(t))
+}
+
+// MatchKeys returns the folded forms of the username and all aliases.
+func (u UserMapping) MatchKeys() []string {
+	var keys []string
+	seen := make(map[string]bool)
+	for _, name := range append([]string{u.Username}, u.Aliases...) {
+		for _, k := range FoldVariants(name) {
+			if !seen[k] {
+				seen[k] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	return keys
+}
+
+// Matches reports whether name is the username or one of the aliases of u,
+// after Unicode folding.
+func (u UserMapping) Matches(name string) bool {
+	variants := FoldVariants(name)
+	for _, k := range u.MatchKeys() {
+		for _, v := range variants {
+			if k == v {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// MatchesFilename reports whether the username or any alias of u appears in filename,
+// after Unicode folding of both sides.
+func (u UserMapping) MatchesFilename(filename string) bool {
+	variants := FoldVariants(filename)
+	for _, k := range u.MatchKeys() {
+		for _, v := range variants {
+			if strings.Contains(v, k) {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // ParseUsers parses username to Discord ID mappings from a comma-separated environment variable
 // Format: "1 Username1 DiscordId1,2 Username2 DiscordId2"
+// Names containing spaces can be double-quoted and any tokens after the Discord ID are
+// treated as aliases, e.g. `1 "Jörg Müller" 123456789012345678 Joerg Jorg`.
 // Returns a slice of UserMapping sorted by the order number.
 func ParseUsers(envVarName string) ([]UserMapping, error) {
 	envVar := os.Getenv(envVarName)
@@ -37,33 +117,45 @@ func ParseUsersFromString(raw string) ([]UserMapping, error) {
 
 func parseUsersFromString(input string) ([]UserMapping, error) {
 	var userMappings []UserMapping
-	pairs := strings.Split(input, ",")
+	pairs, err := splitOutsideQuotes(input, ',')
+	if err != nil {
+		return nil, err
+	}
 	for i, pair := range pairs {
-		parts := strings.SplitN(strings.TrimSpace(pair), " ", 3) // Split into 3 parts: order, username, discordId
-		if len(parts) == 3 {
-			orderStr := parts[0]
-			username := parts[1]
-			discordId := parts[2]
-
-			order, err := strconv.Atoi(orderStr)
-			if err != nil {
-				return nil, fmt.Errorf("invalid order number '%s' in mapping part %d: %w", orderStr, i+1, err)
-			}
+		parts, err := tokenize(pair)
+		if err != nil {
+			return nil, fmt.Errorf("invalid format in mapping part %d: %w", i+1, err)
+		}
+		if len(parts) < 3 {
+			return nil, fmt.Errorf("invalid format in mapping part %d: expected 'order username discordId [alias...]', got '%s'", i+1, strings.TrimSpace(pair))
+		}
 
-			if username != "" && discordId != "" {
-				userMappings = append(userMappings, UserMapping{
-					Order:     order,
-					Username:  username,
-					DiscordID: discordId,
-				})
-			} else {
-				return nil, fmt.Errorf("invalid format in mapping part %d: username or discordId is empty", i+1)
-			}
-		} else {
-			return nil, fmt.Errorf("invalid format in mapping part %d: expected 'order username discordId', got '%s'", i+1, strings.TrimSpace(pair))
+		orderStr := parts[0]
+		username := parts[1]
+		discordId := parts[2]
+
+		order, err := strconv.Atoi(orderStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid order number '%s' in mapping part %d: %w", orderStr, i+1, err)
 		}
+
+		if strings.TrimSpace(username) == "" || discordId == "" {
+			return nil, fmt.Errorf("invalid format in mapping part %d: username or discordId is empty", i+1)
+		}
+
+		userMappings = append(userMappings, UserMapping{
+			Order:     order,
+			Username:  strings.TrimSpace(username),
+			DiscordID: discordId,
+			Aliases:   parts[3:],
+		})
 	}
 
+	return finalizeMappings(userMappings)
+}
+
+// finalizeMappings sorts mappings by order and checks them for duplicates and ambiguous names.
+func finalizeMappings(userMappings []UserMapping) ([]UserMapping, error) {
 	// Sort the mappings by the Order field
 	sort.Slice(userMappings, func(i, j int) bool {
 		return userMappings[i].Order < userMappings[j].Order
@@ -89,5 +181,97 @@ func parseUsersFromString(input string) ([]UserMapping, error) {
 		return nil, fmt.Errorf("no valid user mappings found")
 	}
 
+	for _, mapping := range userMappings {
+		if err := ValidateSnowflake(mapping.DiscordID); err != nil {
+			return nil, fmt.Errorf("invalid Discord ID '%s' for %s: %w", mapping.DiscordID, mapping.Username, err)
+		}
+	}
+
+	if err := checkAmbiguousNames(userMappings); err != nil {
+		return nil, err
+	}
+
 	return userMappings, nil
 }
+
+// checkAmbiguousNames rejects mappings where a name or alias of one player folds to the
+// same key as a name or alias of another player, or contains it (e.g. Bob and Bobby),
+// since filenames are matched by substring and could not be attributed.
+func checkAmbiguousNames(userMappings []UserMapping) error {
+	keys := make([][]string, len(userMappings))
+	for i, m := range userMappings {
+		keys[i] = m.MatchKeys()
+	}
+	for i, m := range userMappings {
+		for j := range userMappings {
+			if i == j {
+				continue
+			}
+			for _, k := range keys[i] {
+				for _, other := range keys[j] {
+					switch {
+					case k == other && i < j:
+						return fmt.Errorf("name '%s' is shared by players %s and %s", k, m.Username, userMappings[j].Username)
+					case k != other && strings.Contains(k, other):
+						return fmt.Errorf("name '%s' of player %s contains '%s' of player %s, so their saves cannot be told apart; use an alias or a more distinct name", k, m.Username, other, userMappings[j].Username)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// splitOutsideQuotes splits s on sep, ignoring separators inside double quotes.
+func splitOutsideQuotes(s string, sep rune) ([]string, error) {
+	var parts []string
+	var cur strings.Builder
+	inQuotes := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			cur.WriteRune(r)
+		case r == sep && !inQuotes:
+			parts = append(parts, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in '%s'", s)
+	}
+	return append(parts, cur.String()), nil
+}
+
+// tokenize splits a single mapping entry on whitespace, keeping double-quoted
+// sections (which may contain spaces) together as one token without the quotes.
+func tokenize(s string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes, quoted := false, false
+	flush := func() {
+		if cur.Len() > 0 || quoted {
+			tokens = append(tokens, cur.String())
+		}
+		cur.Reset()
+		quoted = false
+	}
+	for _, r := range s {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+			quoted = true
+		case unicode.IsSpace(r) && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	if inQuotes {
+		return nil, fmt.Errorf("unterminated quote in '%s'", strings.TrimSpace(s))
+	}
+	flush()
+	return tokens, nil
+}
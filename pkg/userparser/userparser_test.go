@@ -0,0 +1,106 @@
+package userparser
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"1 Alice 123", []string{"1", "Alice", "123"}, false},
+		{"  1\tAlice   123  ", []string{"1", "Alice", "123"}, false},
+		{`1 "Jörg Müller" 123 Joerg Jorg`, []string{"1", "Jörg Müller", "123", "Joerg", "Jorg"}, false},
+		{`1 "Big"Bob 123`, []string{"1", "BigBob", "123"}, false},
+		{`1 "" 123`, []string{"1", "", "123"}, false},
+		{`1 "Jörg Müller 123`, nil, true},
+		{"", nil, false},
+	}
+	for _, tt := range tests {
+		got, err := tokenize(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("tokenize(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSplitOutsideQuotes(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    []string
+		wantErr bool
+	}{
+		{"1 Alice 1,2 Bob 2", []string{"1 Alice 1", "2 Bob 2"}, false},
+		{`1 "Smith, John" 1,2 Bob 2`, []string{`1 "Smith, John" 1`, "2 Bob 2"}, false},
+		{"1 Alice 1,", []string{"1 Alice 1", ""}, false},
+		{"no separator", []string{"no separator"}, false},
+		{`1 "Smith, John 1,2 Bob 2`, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := splitOutsideQuotes(tt.in, ',')
+		if (err != nil) != tt.wantErr {
+			t.Errorf("splitOutsideQuotes(%q) error = %v, want error %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("splitOutsideQuotes(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseUsersFromString(t *testing.T) {
+	users, err := ParseUsersFromString(`2 Bob 223456789012345678, 1 "Jörg Müller" 123456789012345678 Joerg`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(users) != 2 || users[0].Username != "Jörg Müller" || users[1].Username != "Bob" {
+		t.Fatalf("ParseUsersFromString = %+v, want Jörg Müller then Bob", users)
+	}
+	if !slices.Equal(users[0].Aliases, []string{"Joerg"}) {
+		t.Errorf("aliases = %q, want [Joerg]", users[0].Aliases)
+	}
+	for _, name := range []string{"pbem1_turn3_jorg_muller.se1", "PBEM1_turn3_Joerg_Mueller.se1", "pbem1_turn3_joerg.se1"} {
+		if !users[0].MatchesFilename(name) {
+			t.Errorf("Jörg Müller does not match %s", name)
+		}
+	}
+	if users[1].MatchesFilename("pbem1_turn3_jorg_muller.se1") {
+		t.Error("Bob matches Jörg Müller's save")
+	}
+}
+
+func TestCheckAmbiguousNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		users   []UserMapping
+		wantErr string
+	}{
+		{"distinct", []UserMapping{{Username: "Alice"}, {Username: "Bob"}}, ""},
+		{"same name", []UserMapping{{Username: "Bob"}, {Username: "bob"}}, "shared by players"},
+		{"same after folding", []UserMapping{{Username: "Jörg"}, {Username: "Jorg"}}, "shared by players"},
+		{"alias clash", []UserMapping{{Username: "Jörg", Aliases: []string{"Joerg"}}, {Username: "Joerg"}}, "shared by players"},
+		{"transliteration clash", []UserMapping{{Username: "Jörg"}, {Username: "Joerg"}}, "shared by players"},
+		{"prefix", []UserMapping{{Username: "Bob"}, {Username: "Bobby"}}, "contains 'bob'"},
+		{"prefix in reverse order", []UserMapping{{Username: "Bobby"}, {Username: "Bob"}}, "contains 'bob'"},
+		{"alias contains name", []UserMapping{{Username: "Al"}, {Username: "Bob", Aliases: []string{"Albert"}}}, "contains 'al'"},
+		{"underscore spelling", []UserMapping{{Username: "Anna"}, {Username: "Anna Lee"}}, "contains 'anna'"},
+		{"own alias contains own name", []UserMapping{{Username: "Bob", Aliases: []string{"Bobby"}}, {Username: "Alice"}}, ""},
+	}
+	for _, tt := range tests {
+		err := checkAmbiguousNames(tt.users)
+		switch {
+		case tt.wantErr == "" && err != nil:
+			t.Errorf("%s: checkAmbiguousNames = %v, want nil", tt.name, err)
+		case tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)):
+			t.Errorf("%s: checkAmbiguousNames = %v, want an error containing %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+//go:build darwin
+
+package watcher
+
+import "syscall"
+
+// Filesystem type names from statfs(2) whose kqueue events miss remote changes
+var networkTypes = map[string]string{
+	"nfs":     "NFS",
+	"smbfs":   "SMB",
+	"afpfs":   "AFP",
+	"webdav":  "WebDAV",
+	"macfuse": "FUSE",
+	"osxfuse": "FUSE",
+}
+
+// networkFilesystem reports whether dir is on a network filesystem and names it
+func networkFilesystem(dir string) (string, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return "", false
+	}
+	var b []byte
+	for _, c := range st.Fstypename {
+		if c == 0 {
+			break
+		}
+		b = append(b, byte(c))
+	}
+	name, ok := networkTypes[string(b)]
+	return name, ok
+}
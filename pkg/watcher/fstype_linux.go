@@ -0,0 +1,28 @@
+//go:build linux
+
+package watcher
+
+import "syscall"
+
+// Filesystem magic numbers from statfs(2) whose inotify events miss remote changes
+var networkMagic = map[int64]string{
+	0x6969:     "NFS",
+	0x517b:     "SMB",
+	0xff534d42: "CIFS",
+	0xfe534d42: "SMB2",
+	0x65735546: "FUSE",
+	0x01021997: "9P",
+	0x00c36400: "Ceph",
+	0x564c:     "NCP",
+	0x5346414f: "AFS",
+}
+
+// networkFilesystem reports whether dir is on a network filesystem and names it
+func networkFilesystem(dir string) (string, bool) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(dir, &st); err != nil {
+		return "", false
+	}
+	name, ok := networkMagic[int64(st.Type)&0xffffffff]
+	return name, ok
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin && !windows
+
+package watcher
+
+// networkFilesystem cannot detect network filesystems on this platform; use WATCH_MODE=poll for them
+func networkFilesystem(dir string) (string, bool) {
+	return "", false
+}
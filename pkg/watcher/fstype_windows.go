@@ -0,0 +1,20 @@
+//go:build windows
+
+package watcher
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// networkFilesystem reports whether dir is a UNC network share
+func networkFilesystem(dir string) (string, bool) {
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return "", false
+	}
+	if strings.HasPrefix(abs, `\\`) && !strings.HasPrefix(abs, `\\?\`) {
+		return "SMB", true
+	}
+	return "", false
+}
@@ -0,0 +1,119 @@
+package watcher
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watch modes for WATCH_MODE
+const (
+	ModeAuto   = "auto"
+	ModeNotify = "notify"
+	ModePoll   = "poll"
+)
+
+// Modes lists the accepted WATCH_MODE values
+var Modes = []string{ModeAuto, ModeNotify, ModePoll}
+
+// Watcher reports changes in a watch directory. When file notifications are unavailable it
+// falls back to polling and never signals, leaving the monitor to scan on every tick.
+type Watcher struct {
+	// Mode is the effective mode: ModeNotify or ModePoll
+	Mode string
+	// Reason explains why polling was chosen, if it was
+	Reason string
+
+	fsw     *fsnotify.Watcher
+	changes chan struct{}
+	done    chan struct{}
+}
+
+// New watches dir according to mode. Files created, written, renamed or deleted in dir are
+// signalled on Changes. In auto mode, directories on network filesystems are polled because
+// their notifications do not report changes made on other machines.
+func New(dir, mode string) *Watcher {
+	w := &Watcher{Mode: ModePoll, changes: make(chan struct{}, 1), done: make(chan struct{})}
+	switch mode {
+	case ModePoll:
+		w.Reason = "WATCH_MODE is poll"
+		return w
+	case ModeAuto:
+		if fs, ok := networkFilesystem(dir); ok {
+			w.Reason = fmt.Sprintf("%s is on a %s network filesystem", dir, fs)
+			return w
+		}
+	}
+
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		w.Reason = fmt.Sprintf("file notifications are unavailable: %v", err)
+		return w
+	}
+	if err := fsw.Add(dir); err != nil {
+		fsw.Close()
+		w.Reason = fmt.Sprintf("cannot watch %s: %v", dir, err)
+		return w
+	}
+	w.Mode = ModeNotify
+	w.fsw = fsw
+	go w.run()
+	return w
+}
+
+// Polling returns a watcher that never signals, for stores without file notifications.
+func Polling(reason string) *Watcher {
+	return &Watcher{Mode: ModePoll, Reason: reason, changes: make(chan struct{}, 1)}
+}
+
+// Changes signals that the directory changed since the last receive. Bursts of events are
+// coalesced into a single signal. In poll mode the channel never fires.
+func (w *Watcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+// Close stops watching the directory.
+func (w *Watcher) Close() {
+	if w.fsw != nil {
+		w.fsw.Close()
+		<-w.done
+	}
+}
+
+// run forwards relevant events until the underlying watcher is closed
+func (w *Watcher) run() {
+	defer close(w.done)
+	for {
+		select {
+		case ev, ok := <-w.fsw.Events:
+			if !ok {
+				return
+			}
+			// Permission changes don't affect saves
+			if ev.Has(fsnotify.Create) || ev.Has(fsnotify.Write) || ev.Has(fsnotify.Rename) || ev.Has(fsnotify.Remove) {
+				w.signal()
+			}
+		case err, ok := <-w.fsw.Errors:
+			if !ok {
+				return
+			}
+			// Dropped events are recovered by a full scan
+			if errors.Is(err, fsnotify.ErrEventOverflow) {
+				log.Printf("⚠️ File notification queue overflowed; rescanning directory\n")
+			} else {
+				log.Printf("⚠️ File watcher error: %v\n", err)
+			}
+			w.signal()
+		}
+	}
+}
+
+// signal records a change without blocking when one is already pending
+func (w *Watcher) signal() {
+	select {
+	case w.changes <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,88 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// changed reports whether the watcher signals a change within wait
+func changed(w *Watcher, wait time.Duration) bool {
+	select {
+	case <-w.Changes():
+		return true
+	case <-time.After(wait):
+		return false
+	}
+}
+
+func TestNotify(t *testing.T) {
+	dir := t.TempDir()
+	w := New(dir, ModeNotify)
+	defer w.Close()
+	if w.Mode != ModeNotify {
+		t.Skipf("file notifications are unavailable here: %s", w.Reason)
+	}
+
+	save := filepath.Join(dir, "pbem1_turn1_Bob.se1")
+	if err := os.WriteFile(save, []byte("save"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !changed(w, 2*time.Second) {
+		t.Fatal("creating a save did not signal a change")
+	}
+
+	// A burst of events is coalesced into one pending signal
+	for i := 0; i < 5; i++ {
+		if err := os.WriteFile(save, []byte(strings.Repeat("x", i)), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Rename(save, filepath.Join(dir, "pbem1_turn1_Carol.se1")); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if !changed(w, 2*time.Second) {
+		t.Fatal("writing and renaming a save did not signal a change")
+	}
+	if changed(w, 100*time.Millisecond) {
+		t.Error("a burst of events left more than one signal")
+	}
+}
+
+func TestPollMode(t *testing.T) {
+	dir := t.TempDir()
+	w := New(dir, ModePoll)
+	defer w.Close()
+	if w.Mode != ModePoll || w.Reason != "WATCH_MODE is poll" {
+		t.Errorf("New(poll) = mode %s (%s), want poll because of WATCH_MODE", w.Mode, w.Reason)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "pbem1_turn1_Bob.se1"), []byte("save"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if changed(w, 200*time.Millisecond) {
+		t.Error("a polling watcher signalled a change")
+	}
+}
+
+func TestFallbackToPolling(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	for _, mode := range []string{ModeAuto, ModeNotify} {
+		w := New(missing, mode)
+		if w.Mode != ModePoll || !strings.Contains(w.Reason, "cannot watch "+missing) {
+			t.Errorf("New(%s) of a missing directory = mode %s (%s), want polling because it cannot be watched", mode, w.Mode, w.Reason)
+		}
+		w.Close()
+	}
+
+	w := Polling("the save store has no file notifications")
+	defer w.Close()
+	if w.Mode != ModePoll || w.Reason != "the save store has no file notifications" {
+		t.Errorf("Polling = mode %s (%s)", w.Mode, w.Reason)
+	}
+	if changed(w, 50*time.Millisecond) {
+		t.Error("Polling signalled a change")
+	}
+}
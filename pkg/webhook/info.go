@@ -0,0 +1,80 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
+)
+
+// discordHosts are the hosts Discord serves webhooks from
+var discordHosts = map[string]bool{
+	"discord.com":        true,
+	"discordapp.com":     true,
+	"ptb.discord.com":    true,
+	"canary.discord.com": true,
+}
+
+// webhookPath matches /api/webhooks/<id>/<token>, optionally with an API version
+var webhookPath = regexp.MustCompile(`^/api(?:/v\d+)?/webhooks/\d+/[A-Za-z0-9_-]+/?$`)
+
+// Info is the webhook metadata Discord returns for a GET on the webhook URL.
+type Info struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	ChannelID string `json:"channel_id"`
+	GuildID   string `json:"guild_id"`
+}
+
+// CheckURL reports whether webhookURL has the shape of a Discord webhook URL.
+// Error messages never include the token.
+func CheckURL(webhookURL string) error {
+	u, err := url.Parse(webhookURL)
+	if err != nil {
+		return fmt.Errorf("URL cannot be parsed")
+	}
+	if u.Scheme != "https" {
+		return fmt.Errorf("expected https, got '%s'", u.Scheme)
+	}
+	if !discordHosts[strings.ToLower(u.Hostname())] {
+		return fmt.Errorf("host '%s' is not a Discord host", u.Hostname())
+	}
+	if !webhookPath.MatchString(u.Path) {
+		return fmt.Errorf("path does not look like /api/webhooks/<id>/<token>")
+	}
+	return nil
+}
+
+// FetchInfo looks up the webhook with a GET request, which checks the URL and token
+// without posting a message.
+func FetchInfo(ctx context.Context, webhookURL string) (*Info, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, webhookURL, nil)
+	if err != nil {
+		return nil, redact.Error(err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, redact.Error(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var info Info
+		if err := json.Unmarshal(body, &info); err != nil {
+			return nil, fmt.Errorf("decoding webhook info: %w", err)
+		}
+		return &info, nil
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return nil, fmt.Errorf("discord returned status %d: the webhook was deleted or its token is wrong", resp.StatusCode)
+	default:
+		return nil, fmt.Errorf("discord returned status %d", resp.StatusCode)
+	}
+}
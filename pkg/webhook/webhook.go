@@ -8,12 +8,34 @@ import (
 	"log"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/duration"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/game"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/redact"
 	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
 )
 
+// httpClient is shared by all games hosted in this process
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// webhookLocks serializes deliveries per webhook URL, so games sharing a channel
+// do not race each other into Discord's per-webhook rate limit
+var webhookLocks sync.Map
+
+// thumbnail returns the embed thumbnail for imageURL, or nil so profiles without one send none
+func thumbnail(imageURL string) *types.Thumbnail {
+	if imageURL == "" {
+		return nil
+	}
+	return &types.Thumbnail{URL: imageURL}
+}
+
 // prepareWebhookURL adds the wait=true parameter to the webhook URL
 func prepareWebhookURL(webhookURL string) (string, error) {
 	if webhookURL == "" {
@@ -24,7 +46,7 @@ func prepareWebhookURL(webhookURL string) (string, error) {
 	// Add wait=true query parameter to ensure webhook delivery confirmation
 	parsedURL, err := url.Parse(webhookURL)
 	if err != nil {
-		return "", fmt.Errorf("invalid webhook URL: %w", err)
+		return "", fmt.Errorf("invalid webhook URL: %w", redact.Error(err))
 	}
 
 	// Add the wait=true parameter
@@ -48,8 +70,10 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 		return fmt.Errorf("error marshaling JSON: %w", err)
 	}
 
-	// HTTP client with timeout
-	client := &http.Client{Timeout: 10 * time.Second}
+	// One delivery at a time per webhook
+	lock, _ := webhookLocks.LoadOrStore(cfg.WebhookURL, &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
 
 	// Add retry logic
 	maxRetries := 3
@@ -57,8 +81,10 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 		// Send request
 		req, _ := http.NewRequest(http.MethodPost, webhookURL, bytes.NewBuffer(jsonPayload))
 		req.Header.Set("Content-Type", "application/json")
-		resp, err := client.Do(req)
+		resp, err := httpClient.Do(req)
 		if err != nil {
+			// net/http errors include the webhook URL and its token
+			err = redact.Error(err)
 			log.Printf("❌ Attempt %d: Failed to send Discord notification: %v\n", attempt, err)
 			if attempt < maxRetries {
 				time.Sleep(time.Duration(attempt) * time.Second)
@@ -116,28 +142,23 @@ func sendDiscordWebhook(payload *types.DiscordWebhook, username, discordID strin
 // targetUsername/targetDiscordID: The player whose turn it is now (will be pinged)
 // nextPlayerSaveName: The username of the player *after* the target player (used for save instructions)
 func SendWebHook(targetUsername, targetDiscordID, nextPlayerSaveName string, turnNumber int, cfg types.Config) error {
+	profile := cfg.Profile()
 	gameName := cfg.GameName
 
 	// Create webhook payload
 	payload := types.DiscordWebhook{
-		Username:  "Shadow Empire Assistant",
+		Username:  profile.AssistantName,
 		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
 		Content:   fmt.Sprintf("🎲 It's your turn, <@%s>!", targetDiscordID), // Ping the target player
 		Embeds: []types.Embed{
 			{
-				Color: 0xFFA500,
-				Thumbnail: types.Thumbnail{
-					URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
-				},
+				Color:     0xFFA500,
+				Thumbnail: thumbnail(profile.ThumbnailURL),
 				Fields: []types.Field{
 					{
 						Name: "📋 Save File Instructions",
 						// Instruct to save for the player *after* the current one and how to resign
-						Value: fmt.Sprintf(
-							"After completing your turn, save the file as:\n```\n%s_turn%d_%s\n```If the next player is no longer playing, create this file:\n```\nresign_%s\n```",
-							gameName, turnNumber, nextPlayerSaveName,
-							nextPlayerSaveName,
-						),
+						Value: saveInstructions(profile, gameName, turnNumber, nextPlayerSaveName),
 					},
 				},
 				Footer: types.Footer{
@@ -152,26 +173,25 @@ func SendWebHook(targetUsername, targetDiscordID, nextPlayerSaveName string, tur
 	return sendDiscordWebhook(&payload, targetUsername, targetDiscordID, false, cfg)
 }
 
-// SendRenameWebHook sends a Discord webhook notification asking to rename a file
-func SendRenameWebHook(username, discordID, filename string, turnNumber int, cfg types.Config) error {
+// SendRenameWebHook sends a Discord webhook notification asking to rename a file to correctedName
+func SendRenameWebHook(username, discordID, filename, correctedName string, cfg types.Config) error {
+	profile := cfg.Profile()
 	gameName := cfg.GameName
 
 	// Create webhook payload
 	payload := types.DiscordWebhook{
-		Username:  "Shadow Empire Assistant",
+		Username:  profile.AssistantName,
 		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
 		Content:   fmt.Sprintf("⚠️ File naming issue detected in your save, <@%s>!", discordID),
 		Embeds: []types.Embed{
 			{
-				Color: 0xFF0000, // Red color for warning
-				Thumbnail: types.Thumbnail{
-					URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
-				},
+				Color:     0xFF0000, // Red color for warning
+				Thumbnail: thumbnail(profile.ThumbnailURL),
 				Fields: []types.Field{
 					{
 						Name: "📋 File Rename Required",
-						Value: fmt.Sprintf("The save file you created `%s` doesn't match the configured game name.\n\nPlease rename it to follow the format:\n```\n%s_turn%d_%s\n```\n*(Replace %s with the next player's name)*",
-							filename, gameName, turnNumber, "[NextPlayerName]", "[NextPlayerName]"),
+						Value: fmt.Sprintf("The save file you created `%s` doesn't match the configured game name `%s`.\n\nPlease rename it to:\n```\n%s\n```",
+							filename, gameName, correctedName),
 					},
 				},
 				Footer: types.Footer{
@@ -185,41 +205,61 @@ func SendRenameWebHook(username, discordID, filename string, turnNumber int, cfg
 	return sendDiscordWebhook(&payload, username, discordID, true, cfg)
 }
 
+// SendAutoRenameWebHook tells the player who saved a misnamed file that the bot fixed the name itself.
+// action is "copy" or "rename".
+func SendAutoRenameWebHook(username, discordID, filename, correctedName, action string, cfg types.Config) error {
+	profile := cfg.Profile()
+
+	verb := "renamed"
+	if action == types.AutoRenameCopy {
+		verb = "copied"
+	}
+
+	payload := types.DiscordWebhook{
+		Username:  profile.AssistantName,
+		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
+		Content:   fmt.Sprintf("🛠️ I fixed the name of your save, <@%s>.", discordID),
+		Embeds: []types.Embed{
+			{
+				Color:     0x2ECC71, // Green for an automatic fix
+				Thumbnail: thumbnail(profile.ThumbnailURL),
+				Fields: []types.Field{
+					{
+						Name: "📋 File Name Corrected",
+						Value: fmt.Sprintf("`%s` didn't match the expected naming format, so it was %s to:\n```\n%s\n```Please use this format next time.",
+							filename, verb, correctedName),
+					},
+				},
+				Footer:    types.Footer{Text: "Made with ❤️ by Solon"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	return sendDiscordWebhook(&payload, username, discordID, true, cfg)
+}
+
 // SendReminderWebHook sends a Discord webhook notification reminding a player it's their turn
-func SendReminderWebHook(username, discordID, nextPlayerSaveName string, turnNumber int, minutesElapsed int, cfg types.Config) error {
+func SendReminderWebHook(username, discordID, nextPlayerSaveName string, turnNumber int, elapsed time.Duration, cfg types.Config) error {
+	profile := cfg.Profile()
 	gameName := cfg.GameName
 
-	// Format elapsed time as hours and minutes for display
-	hours := minutesElapsed / 60
-	minutes := minutesElapsed % 60
-	var timeElapsedText string
-	if hours > 0 && minutes > 0 {
-		timeElapsedText = fmt.Sprintf("%d hours and %d minutes", hours, minutes)
-	} else if hours > 0 {
-		timeElapsedText = fmt.Sprintf("%d hours", hours)
-	} else {
-		timeElapsedText = fmt.Sprintf("%d minutes", minutes)
-	}
+	// Format elapsed time in whole minutes for display
+	timeElapsedText := duration.Format(elapsed.Truncate(time.Minute))
 
 	// Create webhook payload
 	payload := types.DiscordWebhook{
-		Username:  "Shadow Empire Assistant",
+		Username:  profile.AssistantName,
 		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
 		Content:   fmt.Sprintf("⏰ Reminder! It's still your turn, <@%s>! (%s elapsed)", discordID, timeElapsedText),
 		Embeds: []types.Embed{
 			{
-				Color: 0xFF9900, // Orange-yellow for reminder
-				Thumbnail: types.Thumbnail{
-					URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg",
-				},
+				Color:     0xFF9900, // Orange-yellow for reminder
+				Thumbnail: thumbnail(profile.ThumbnailURL),
 				Fields: []types.Field{
 					{
-						Name: "📋 Save File Instructions",
-						Value: fmt.Sprintf(
-							"After completing your turn, save the file as:\n```\n%s_turn%d_%s\n```If the next player is no longer playing, create this file:\n```\nresign_%s\n```",
-							gameName, turnNumber, nextPlayerSaveName,
-							nextPlayerSaveName,
-						),
+						Name:  "📋 Save File Instructions",
+						Value: saveInstructions(profile, gameName, turnNumber, nextPlayerSaveName),
 					},
 				},
 				Footer: types.Footer{
@@ -234,6 +274,15 @@ func SendReminderWebHook(username, discordID, nextPlayerSaveName string, turnNum
 	return sendDiscordWebhook(&payload, username, discordID, false, cfg)
 }
 
+// saveInstructions builds the save file instructions shown in turn and reminder notifications
+func saveInstructions(profile game.Profile, gameName string, turnNumber int, nextPlayerSaveName string) string {
+	return fmt.Sprintf(
+		"%s\n```\n%s\n```If the next player is no longer playing, create this file:\n```\nresign_%s\n```",
+		profile.SaveInstruction, profile.FormatSaveName(gameName, turnNumber, nextPlayerSaveName),
+		nextPlayerSaveName,
+	)
+}
+
 // maskID masks Discord IDs in logs
 func maskID(id string) string {
 	if len(id) <= 4 {
@@ -244,15 +293,16 @@ func maskID(id string) string {
 
 // SendResignationWebHook announces that a player has resigned from the game
 func SendResignationWebHook(username, discordID string, cfg types.Config) error {
+	profile := cfg.Profile()
 	// Create webhook payload matching the style of other messages
 	payload := types.DiscordWebhook{
-		Username:  "Shadow Empire Assistant",
+		Username:  profile.AssistantName,
 		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
 		Content:   fmt.Sprintf("🚪 <@%s> has resigned from %s.", discordID, cfg.GameName),
 		Embeds: []types.Embed{
 			{
 				Color:     0xFF0000, // Red tone for resignation
-				Thumbnail: types.Thumbnail{URL: "https://upload.wikimedia.org/wikipedia/en/4/4f/Shadow_Empire_cover.jpg"},
+				Thumbnail: thumbnail(profile.ThumbnailURL),
 				Fields: []types.Field{
 					{
 						Name:  "📋 Turn Order Update",
@@ -268,6 +318,39 @@ func SendResignationWebHook(username, discordID string, cfg types.Config) error
 	return sendDiscordWebhook(&payload, username, discordID, false, cfg)
 }
 
+// SendSyncConflictWebHook reports competing versions of a save left behind by a sync tool.
+// versions describes each copy, starting with the original if it still exists. The player is
+// pinged when discordID is set.
+func SendSyncConflictWebHook(username, discordID, tool, original string, versions []string, cfg types.Config) error {
+	profile := cfg.Profile()
+	content := fmt.Sprintf("⚠️ Sync conflict detected for a %s save!", cfg.GameName)
+	if discordID != "" {
+		content = fmt.Sprintf("⚠️ Sync conflict detected for your save, <@%s>!", discordID)
+	}
+	payload := types.DiscordWebhook{
+		Username:  profile.AssistantName,
+		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
+		Content:   content,
+		Embeds: []types.Embed{
+			{
+				Color:     0xFF0000, // Red color for warning
+				Thumbnail: thumbnail(profile.ThumbnailURL),
+				Fields: []types.Field{
+					{
+						Name: "🔀 Sync Conflict",
+						Value: fmt.Sprintf("%s found competing versions of `%s`:\n- %s\n\nKeep the right one under the original name and delete the others.",
+							tool, original, strings.Join(versions, "\n- ")),
+					},
+				},
+				Footer:    types.Footer{Text: "Made with ❤️ by Solon"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	return sendDiscordWebhook(&payload, username, discordID, false, cfg)
+}
+
 // parseRetryAfter figures out how long to wait from Discord rate limit headers
 func parseRetryAfter(h http.Header) time.Duration {
 	// Prefer Retry-After (seconds or date), or X-RateLimit-Reset-After (seconds)
@@ -285,3 +368,146 @@ func parseRetryAfter(h http.Header) time.Duration {
 	// fallback: 3 seconds
 	return 3 * time.Second
 }
+
+// maxFieldLength is Discord's limit on the length of an embed field value
+const maxFieldLength = 1024
+
+// truncateField shortens s to at most limit bytes without splitting a character, marking the cut
+func truncateField(s string, limit int) string {
+	if len(s) <= limit {
+		return s
+	}
+	cut := limit - len("…")
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…"
+}
+
+// joinFieldLines joins lines into an embed field value, replacing the lines that don't fit
+// with a count so the field stays within Discord's limit
+func joinFieldLines(lines []string) string {
+	var b strings.Builder
+	for i, line := range lines {
+		// Leave room for the count unless this is the last line
+		room := maxFieldLength
+		if i < len(lines)-1 {
+			room -= len("\n…and 9999 more")
+		}
+		if i > 0 {
+			room -= b.Len() + 1
+		}
+		if len(line) > room {
+			if i == 0 {
+				b.WriteString(truncateField(line, room))
+				i++
+			}
+			if i < len(lines) {
+				fmt.Fprintf(&b, "\n…and %d more", len(lines)-i)
+			}
+			return b.String()
+		}
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
+
+// SendRosterUpdateWebHook announces changes applied from an edited roster file
+func SendRosterUpdateWebHook(changes []string, cfg types.Config) error {
+	profile := cfg.Profile()
+	payload := types.DiscordWebhook{
+		Username:  profile.AssistantName,
+		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
+		Content:   fmt.Sprintf("📇 The player roster for %s has been updated.", cfg.GameName),
+		Embeds: []types.Embed{
+			{
+				Color:     0x3498DB, // Blue for informational updates
+				Thumbnail: thumbnail(profile.ThumbnailURL),
+				Fields: []types.Field{
+					{
+						Name:  "📋 Roster Changes",
+						Value: joinFieldLines(changes),
+					},
+				},
+				Footer:    types.Footer{Text: "Made with ❤️ by Solon"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	return sendDiscordWebhook(&payload, "roster", "", false, cfg)
+}
+
+// SendRosterErrorWebHook reports a roster edit that was rejected because it failed validation
+func SendRosterErrorWebHook(path string, rosterErr error, cfg types.Config) error {
+	profile := cfg.Profile()
+	payload := types.DiscordWebhook{
+		Username:  profile.AssistantName,
+		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
+		Content:   fmt.Sprintf("⚠️ The latest edit to the %s roster was rejected.", cfg.GameName),
+		Embeds: []types.Embed{
+			{
+				Color:     0xFF0000, // Red color for warning
+				Thumbnail: thumbnail(profile.ThumbnailURL),
+				Fields: []types.Field{
+					{
+						Name:  "📋 Roster Error",
+						Value: fmt.Sprintf("`%s` could not be applied:\n```\n%s\n```The previous roster stays active until the file is fixed.", filepath.Base(path), truncateField(rosterErr.Error(), 800)),
+					},
+				},
+				Footer:    types.Footer{Text: "Made with ❤️ by Solon"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	return sendDiscordWebhook(&payload, "roster", "", false, cfg)
+}
+
+// SendAdminAlertWebHook posts an operational alert to the admin channel (ADMIN_WEBHOOK_URL, falling
+// back to the game webhook), mentioning the given admin Discord IDs
+func SendAdminAlertWebHook(title, details string, adminIDs []string, cfg types.Config) error {
+	profile := cfg.Profile()
+
+	mentions := make([]string, 0, len(adminIDs))
+	for _, id := range adminIDs {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+	}
+	content := fmt.Sprintf("🚨 %s", title)
+	if len(mentions) > 0 {
+		content += " " + strings.Join(mentions, " ")
+	}
+
+	// Error details may quote configuration values
+	details = redact.String(details)
+
+	details = truncateField(details, maxFieldLength-24)
+
+	payload := types.DiscordWebhook{
+		Username:  profile.AssistantName,
+		AvatarURL: "https://raw.githubusercontent.com/auricom/home-ops/main/docs/src/assets/logo.png",
+		Content:   content,
+		Embeds: []types.Embed{
+			{
+				Color:     0x8E44AD, // Purple for admin alerts
+				Thumbnail: thumbnail(profile.ThumbnailURL),
+				Fields: []types.Field{
+					{
+						Name:  "🛠️ Details",
+						Value: fmt.Sprintf("```\n%s\n```", details),
+					},
+				},
+				Footer:    types.Footer{Text: "Made with ❤️ by Solon"},
+				Timestamp: time.Now().Format(time.RFC3339),
+			},
+		},
+	}
+
+	if cfg.AdminWebhookURL != "" {
+		cfg.WebhookURL = cfg.AdminWebhookURL
+	}
+	return sendDiscordWebhook(&payload, "admins", "", false, cfg)
+}
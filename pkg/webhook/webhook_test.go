@@ -0,0 +1,102 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+)
+
+func TestTruncateField(t *testing.T) {
+	if got := truncateField("short", 10); got != "short" {
+		t.Errorf("truncateField kept %q, want it unchanged", got)
+	}
+
+	long := strings.Repeat("ü", 600) // 1200 bytes
+	got := truncateField(long, maxFieldLength)
+	if len(got) > maxFieldLength {
+		t.Errorf("truncateField returned %d bytes, want at most %d", len(got), maxFieldLength)
+	}
+	if !utf8.ValidString(got) || !strings.HasSuffix(got, "…") {
+		t.Errorf("truncateField split a character or dropped the marker: %q", got[len(got)-8:])
+	}
+}
+
+func TestJoinFieldLines(t *testing.T) {
+	few := []string{"➕ Added Alice", "➖ Removed Bob"}
+	if got := joinFieldLines(few); got != "➕ Added Alice\n➖ Removed Bob" {
+		t.Errorf("joinFieldLines(%q) = %q", few, got)
+	}
+
+	var many []string
+	for i := 0; i < 200; i++ {
+		many = append(many, fmt.Sprintf("✏️ Player%03d: discord_id changed", i))
+	}
+	got := joinFieldLines(many)
+	if len(got) > maxFieldLength {
+		t.Fatalf("joinFieldLines returned %d bytes, want at most %d", len(got), maxFieldLength)
+	}
+	lines := strings.Split(got, "\n")
+	want := fmt.Sprintf("…and %d more", len(many)-(len(lines)-1))
+	if last := lines[len(lines)-1]; last != want {
+		t.Errorf("last line = %q, want %q", last, want)
+	}
+
+	huge := []string{strings.Repeat("x", 2000), "second"}
+	got = joinFieldLines(huge)
+	if len(got) > maxFieldLength || !strings.HasSuffix(got, "…and 1 more") {
+		t.Errorf("joinFieldLines with an oversized first line = %d bytes ending %q", len(got), got[len(got)-20:])
+	}
+}
+
+// captureWebhook starts a stand-in for a Discord webhook and returns the raw payloads it receives
+func captureWebhook(t *testing.T) (string, *[][]byte) {
+	t.Helper()
+	var payloads [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		payloads = append(payloads, body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv.URL + "/api/webhooks/1/token", &payloads
+}
+
+func TestGenericProfileOmitsThumbnail(t *testing.T) {
+	for _, tc := range []struct {
+		profile   string
+		thumbnail bool
+	}{
+		{"generic", false},
+		{"shadow-empire", true},
+	} {
+		webhookURL, payloads := captureWebhook(t)
+		cfg := types.Config{GameName: "PBEM1", GameProfile: tc.profile, WebhookURL: webhookURL}
+		if err := SendWebHook("Bob", "223456789012345678", "Alice", 3, cfg); err != nil {
+			t.Fatalf("%s: SendWebHook: %v", tc.profile, err)
+		}
+		if len(*payloads) != 1 {
+			t.Fatalf("%s: got %d webhook calls, want 1", tc.profile, len(*payloads))
+		}
+
+		var payload struct {
+			Embeds []map[string]json.RawMessage `json:"embeds"`
+		}
+		if err := json.Unmarshal((*payloads)[0], &payload); err != nil {
+			t.Fatalf("%s: invalid payload: %v", tc.profile, err)
+		}
+		_, ok := payload.Embeds[0]["thumbnail"]
+		if ok != tc.thumbnail {
+			t.Errorf("%s: embed has thumbnail = %v, want %v: %s", tc.profile, ok, tc.thumbnail, (*payloads)[0])
+		}
+		if !strings.Contains(string((*payloads)[0]), "PBEM1_turn3_Alice") {
+			t.Errorf("%s: payload lacks the save name: %s", tc.profile, (*payloads)[0])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/types"
+	"github.com/1Solon/shadow-empire-pbem-bot/pkg/webhook"
+)
+
+// configCheckInterval is how often the config file is checked for changes
+const configCheckInterval = 5 * time.Second
+
+// reloader re-reads the configuration on SIGHUP or when the config file changes
+// and hands safe changes to the running monitors.
+type reloader struct {
+	opts    types.LoadOptions
+	games   []*types.LiveConfig
+	modTime time.Time
+	size    int64
+}
+
+func newReloader(opts types.LoadOptions, games []*types.LiveConfig) *reloader {
+	r := &reloader{opts: opts, games: games}
+	r.fileChanged()
+	return r
+}
+
+// run waits for reload triggers until ctx is canceled
+func (r *reloader) run(ctx context.Context) {
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	defer signal.Stop(hupCh)
+
+	ticker := time.NewTicker(configCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hupCh:
+			r.reload("SIGHUP")
+		case <-ticker.C:
+			if r.opts.ConfigFile != "" && r.fileChanged() {
+				r.reload("config file changed")
+			}
+		}
+	}
+}
+
+// fileChanged reports whether the config file was modified since the last call
+func (r *reloader) fileChanged() bool {
+	if r.opts.ConfigFile == "" {
+		return false
+	}
+	info, err := os.Stat(r.opts.ConfigFile)
+	if err != nil {
+		return false
+	}
+	if info.ModTime().Equal(r.modTime) && info.Size() == r.size {
+		return false
+	}
+	r.modTime, r.size = info.ModTime(), info.Size()
+	return true
+}
+
+// reload loads and validates the new configuration and applies it to every game, or to
+// none of them if any game's configuration is invalid
+func (r *reloader) reload(reason string) {
+	log.Printf("🔄 Reloading configuration (%s)\n", reason)
+
+	// Pick up edits to the .env file loaded at startup
+	if dotEnvKeys != nil {
+		if err := applyDotEnv(); err != nil {
+			log.Printf("⚠️ Error reloading .env file: %v\n", err)
+		}
+	}
+
+	games, err := types.Load(r.opts)
+	if err != nil {
+		r.reject([]string{err.Error()})
+		return
+	}
+
+	var fatal []string
+	for _, g := range games {
+		var verr *types.ValidationError
+		if err := g.Validate(); errors.As(err, &verr) {
+			for _, p := range verr.Problems {
+				if p.Fatal {
+					fatal = append(fatal, g.Label()+p.String())
+				}
+			}
+		}
+	}
+	if len(fatal) > 0 {
+		r.reject(fatal)
+		return
+	}
+
+	// Games can only be added or removed with a restart
+	running := make(map[string]bool, len(r.games))
+	for _, live := range r.games {
+		running[live.Get().ID] = true
+	}
+	for _, g := range games {
+		if !running[g.ID] {
+			log.Printf("⚠️ Game %s was added to the configuration; restart the bot to start it\n", g.ID)
+		}
+	}
+
+	applied := false
+	for _, live := range r.games {
+		current := live.Get()
+		updated, ok := findGame(games, current.ID)
+		if !ok {
+			log.Printf("⚠️ Game %s was removed from the configuration; restart the bot to stop it\n", current.ID)
+			continue
+		}
+		merged, changes := types.Reload(current, updated)
+		if len(changes) == 0 {
+			continue
+		}
+		live.Set(merged)
+		applied = true
+
+		lines := make([]string, 0, len(changes))
+		for _, c := range changes {
+			lines = append(lines, c.String())
+		}
+		log.Printf("🔄 %sConfiguration changes:\n", merged.Label())
+		for _, line := range lines {
+			log.Printf("  - %s\n", line)
+		}
+		if merged.ConfigChangeAlerts {
+			if err := webhook.SendAdminAlertWebHook("Configuration reloaded", strings.Join(lines, "\n"), nil, merged); err != nil {
+				log.Printf("❌ Failed to send configuration change alert: %v\n", err)
+			}
+		}
+	}
+	if !applied {
+		log.Println("🔄 Configuration unchanged")
+	}
+}
+
+// reject logs why a reload was refused and keeps the running configuration
+func (r *reloader) reject(problems []string) {
+	log.Println("❌ Configuration reload rejected, keeping the current configuration:")
+	for _, p := range problems {
+		log.Printf("  - %s\n", p)
+	}
+	cfg := r.games[0].Get()
+	if cfg.ConfigChangeAlerts {
+		if err := webhook.SendAdminAlertWebHook("Configuration reload rejected", strings.Join(problems, "\n"), nil, cfg); err != nil {
+			log.Printf("❌ Failed to send configuration change alert: %v\n", err)
+		}
+	}
+}
+
+// findGame returns the game with the given ID
+func findGame(games []types.Config, id string) (types.Config, bool) {
+	for _, g := range games {
+		if g.ID == id {
+			return g, true
+		}
+	}
+	return types.Config{}, false
+}
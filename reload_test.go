@@ -0,0 +1,47 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestApplyDotEnv(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Cleanup(func() { dotEnvKeys = nil })
+	t.Setenv("TEST_SYSTEM", "system")
+	for _, key := range []string{"TEST_KEPT", "TEST_DROPPED"} {
+		t.Setenv(key, "")
+		os.Unsetenv(key)
+	}
+
+	write := func(content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, ".env"), []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("TEST_SYSTEM=file\nTEST_KEPT=one\nTEST_DROPPED=x\n")
+	if err := applyDotEnv(); err != nil {
+		t.Fatalf("applyDotEnv: %v", err)
+	}
+	if got := os.Getenv("TEST_KEPT"); got != "one" {
+		t.Errorf("TEST_KEPT = %q after loading, want one", got)
+	}
+
+	// A reload replaces and clears what .env set, but never the system environment
+	write("TEST_SYSTEM=file\nTEST_KEPT=two\n")
+	if err := applyDotEnv(); err != nil {
+		t.Fatalf("applyDotEnv on reload: %v", err)
+	}
+	if got := os.Getenv("TEST_KEPT"); got != "two" {
+		t.Errorf("TEST_KEPT = %q after reloading, want two", got)
+	}
+	if _, set := os.LookupEnv("TEST_DROPPED"); set {
+		t.Error("TEST_DROPPED is still set after it was removed from .env")
+	}
+	if got := os.Getenv("TEST_SYSTEM"); got != "system" {
+		t.Errorf("TEST_SYSTEM = %q, want the system value", got)
+	}
+}